@@ -0,0 +1,106 @@
+package freenom
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"terraform-provider-frenom/freenom/client"
+	"time"
+)
+
+var _ FreenomClient = &cacheClient{}
+
+// cacheClient keeps the DomainInfo of every domain for ttl, so refreshing many records of
+// the same domain reads it only once. The domain is invalidated whenever the provider writes to it.
+type cacheClient struct {
+	FreenomClient
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// generations counts the writes to every domain, a read started before a write is not cached
+	generations map[string]uint64
+}
+
+type cacheEntry struct {
+	info    *client.DomainInfo
+	expires time.Time
+}
+
+func newCacheClient(c FreenomClient, ttl time.Duration) *cacheClient {
+	return &cacheClient{
+		FreenomClient: c,
+		ttl:           ttl,
+		now:           time.Now,
+		entries:       make(map[string]*cacheEntry),
+		generations:   make(map[string]uint64),
+	}
+}
+
+func (c *cacheClient) GetDomainInfo(ctx context.Context, domain string) (*client.DomainInfo, error) {
+	if c.ttl <= 0 {
+		return c.FreenomClient.GetDomainInfo(ctx, domain)
+	}
+
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	generation := c.generations[key]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return entry.info, nil
+	}
+
+	info, err := c.FreenomClient.GetDomainInfo(ctx, domain)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generations[key] == generation {
+		c.entries[key] = &cacheEntry{
+			info:    info,
+			expires: c.now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+
+	return info, nil
+}
+
+// invalidate drops the cached domain, it is called before and after every write
+func (c *cacheClient) invalidate(domain string) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	c.generations[key]++
+}
+
+func (c *cacheClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	c.invalidate(domain)
+	defer c.invalidate(domain)
+
+	return c.FreenomClient.AddRecord(ctx, domain, records)
+}
+
+func (c *cacheClient) ModifyRecord(ctx context.Context, domain string, oldRecord, newRecord *client.DomainRecord) error {
+	c.invalidate(domain)
+	defer c.invalidate(domain)
+
+	return c.FreenomClient.ModifyRecord(ctx, domain, oldRecord, newRecord)
+}
+
+func (c *cacheClient) DeleteRecord(ctx context.Context, domain string, record *client.DomainRecord) error {
+	c.invalidate(domain)
+	defer c.invalidate(domain)
+
+	return c.FreenomClient.DeleteRecord(ctx, domain, record)
+}
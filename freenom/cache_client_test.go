@@ -0,0 +1,96 @@
+package freenom
+
+import (
+	"context"
+	"sync"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+	"time"
+)
+
+// readCountingClient counts the GetDomainInfo calls reaching Freenom
+type readCountingClient struct {
+	*client.Fake
+
+	mu    sync.Mutex
+	reads int
+}
+
+func (c *readCountingClient) GetDomainInfo(ctx context.Context, domain string) (*client.DomainInfo, error) {
+	c.mu.Lock()
+	c.reads++
+	c.mu.Unlock()
+
+	return c.Fake.GetDomainInfo(ctx, domain)
+}
+
+func newTestCacheClient(ttl time.Duration) (*cacheClient, *readCountingClient) {
+	counting := &readCountingClient{
+		Fake: newTestFake(client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600}),
+	}
+	return newCacheClient(counting, ttl), counting
+}
+
+func TestCacheClientReusesDomainInfo(t *testing.T) {
+	c, counting := newTestCacheClient(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		info, err := c.GetDomainInfo(context.Background(), "example.tk")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(info.Records) != 1 {
+			t.Fatalf("expected 1 record, got %d", len(info.Records))
+		}
+	}
+
+	if counting.reads != 1 {
+		t.Errorf("expected a single read, got %d", counting.reads)
+	}
+}
+
+func TestCacheClientExpires(t *testing.T) {
+	c, counting := newTestCacheClient(time.Minute)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.GetDomainInfo(context.Background(), "example.tk")
+	now = now.Add(2 * time.Minute)
+	c.GetDomainInfo(context.Background(), "example.tk")
+
+	if counting.reads != 2 {
+		t.Errorf("expected the expired domain to be read again, got %d reads", counting.reads)
+	}
+}
+
+func TestCacheClientInvalidatesOnWrite(t *testing.T) {
+	c, counting := newTestCacheClient(time.Minute)
+
+	c.GetDomainInfo(context.Background(), "example.tk")
+
+	err := c.AddRecord(context.Background(), "example.tk", []client.DomainRecord{{Type: "A", Name: "api", Value: "10.10.10.11", TTL: 3600}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	info, err := c.GetDomainInfo(context.Background(), "example.tk")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if counting.reads != 2 || len(info.Records) != 2 {
+		t.Errorf("expected the domain to be read again after the write, got %d reads and %d records", counting.reads, len(info.Records))
+	}
+}
+
+func TestCacheClientDisabled(t *testing.T) {
+	c, counting := newTestCacheClient(0)
+
+	c.GetDomainInfo(context.Background(), "example.tk")
+	c.GetDomainInfo(context.Background(), "example.tk")
+
+	if counting.reads != 2 {
+		t.Errorf("expected every read to reach Freenom, got %d reads", counting.reads)
+	}
+}
@@ -0,0 +1,31 @@
+package freenom
+
+import (
+	"context"
+	"terraform-provider-frenom/freenom/client"
+)
+
+// FreenomClient is the subset of the Freenom client area used by resources and data sources.
+// It is implemented by client.Client and by the in-memory client.Fake used in unit tests.
+type FreenomClient interface {
+	GetDomainInfo(ctx context.Context, domain string) (*client.DomainInfo, error)
+	AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error
+	ModifyRecord(ctx context.Context, domain string, oldRecord, newRecord *client.DomainRecord) error
+	DeleteRecord(ctx context.Context, domain string, record *client.DomainRecord) error
+}
+
+var _ FreenomClient = &client.Client{}
+var _ FreenomClient = &client.Fake{}
+
+// newFreenomClient creates a client for the given account without any network call,
+// it logs in the first time a resource or data source uses it
+func newFreenomClient(ctx context.Context, config client.Config, username, password string) (FreenomClient, error) {
+	c, err := client.New(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.SetCredentials(username, password)
+	return c, nil
+}
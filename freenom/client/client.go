@@ -0,0 +1,549 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const timeout time.Duration = time.Second * 20
+
+// DefaultBaseURL is the Freenom client area, used when Config.BaseURL is not set
+const DefaultBaseURL string = "https://my.freenom.com/"
+
+var reToken = regexp.MustCompile(`(?is:class="form-stacked".+?value="([^"]+?)")`)
+var reLoggedIn = regexp.MustCompile(`(?is:<span class="hidden-sm">Hello.+?</span>)`)
+
+// Default retry settings, used by the provider when they are not configured
+const (
+	DefaultMaxRetries   = 4
+	DefaultRetryWaitMin = time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// Config configures how the Client talks to Freenom
+type Config struct {
+	// MaxRetries is how many times a request failed for a transient error is sent again
+	MaxRetries int
+	// RetryWaitMin and RetryWaitMax bound the jittered exponential backoff between the retries
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// RequestsPerMinute limits the requests sent to Freenom, including the logins. 0 disables the limit.
+	RequestsPerMinute int
+
+	// BaseURL replaces DefaultBaseURL, to use a stand-in server
+	BaseURL string
+	// ProxyURL is the HTTP proxy of the requests, its user info is sent as the proxy credentials.
+	// When it is not set the proxy of the HTTPS_PROXY and HTTP_PROXY environment variables is used.
+	ProxyURL string
+	// CACertFile is a PEM file of certificate authorities trusted in addition to the system ones
+	CACertFile         string
+	InsecureSkipVerify bool
+	// UserAgent is sent with every request when it is not empty
+	UserAgent string
+
+	// TraceFile is a file where every request and response is appended, with the credentials and cookies redacted.
+	// Empty disables the trace.
+	TraceFile string
+
+	// SessionCachePath is a file where the session is saved encrypted with the credentials,
+	// so the next clients reuse it instead of logging in until it expires. Empty disables it.
+	SessionCachePath string
+}
+
+// Client is a Freenom client area session.
+// Every Client has its own cookie jar, so several accounts can be used at the same time.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	config     Config
+	limiter    *rateLimiter
+
+	// loginMu serializes the logins, so an expired session is renewed only once
+	loginMu sync.Mutex
+
+	mu       sync.Mutex
+	username string
+	password string
+	loggedIn bool
+	token    string
+	// loginErr is the refused first login, not tried again
+	loginErr error
+	// logins counts the sessions opened, it tells apart a session already renewed by another request
+	logins  uint64
+	domains map[string]*DomainInfo
+}
+
+func New(config Config) (*Client, error) {
+	jar, err := cookiejar.New(nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("creating cookie jar: %w", err)
+	}
+
+	baseURL, err := parseBaseURL(config.BaseURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		httpClient: &http.Client{
+			Jar:       jar,
+			Timeout:   timeout,
+			Transport: transport,
+		},
+		baseURL: baseURL,
+		config:  config,
+		domains: make(map[string]*DomainInfo),
+	}
+
+	if config.TraceFile != "" {
+		// the trace redacts the username as well, as it is meant to be shared
+		trace, err := newTraceTransport(transport, config.TraceFile, func() []string {
+			c.mu.Lock()
+			username := c.username
+			c.mu.Unlock()
+
+			return append(c.secrets(), username)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+		c.httpClient.Transport = trace
+	}
+
+	if config.RequestsPerMinute > 0 {
+		c.limiter = newRateLimiter(config.RequestsPerMinute)
+	}
+	return c, nil
+}
+
+// parseBaseURL checks the base url and returns it ending with a slash, or DefaultBaseURL when it is empty
+func parseBaseURL(rawURL string) (string, error) {
+	if rawURL == "" {
+		return DefaultBaseURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid base url %q, expected an http or https url", rawURL)
+	}
+
+	return strings.TrimSuffix(rawURL, "/") + "/", nil
+}
+
+// newTransport returns the transport of the requests with the proxy and TLS settings of config
+func newTransport(config Config) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.CACertFile == "" && !config.InsecureSkipVerify {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACertFile != "" {
+		pem, err := os.ReadFile(config.CACertFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("reading ca cert file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificate found in ca cert file %s", config.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+func (c *Client) clientAreaURL() string {
+	return c.baseURL + "clientarea.php"
+}
+
+// SetCredentials sets the account of the client without sending any request,
+// the first request needing a session logs in with it
+func (c *Client) SetCredentials(username, password string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.username = username
+	c.password = password
+}
+
+// Login opens a new session for the given account, or restores the one saved in the session cache.
+// The credentials are kept to log in again when the session expires.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	return c.openSession(ctx, username, password)
+}
+
+// lazyLogin opens the first session with the credentials set by SetCredentials.
+// Concurrent requests wait for a single login, and refused credentials are not tried again.
+func (c *Client) lazyLogin(ctx context.Context) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	c.mu.Lock()
+	loggedIn, username, password, loginErr := c.loggedIn, c.username, c.password, c.loginErr
+	c.mu.Unlock()
+
+	if loggedIn {
+		return nil
+	}
+	if loginErr != nil {
+		return loginErr
+	}
+	if username == "" {
+		return fmt.Errorf("not logged in")
+	}
+
+	err := c.openSession(ctx, username, password)
+
+	if errors.Is(err, ErrAuthentication) {
+		c.mu.Lock()
+		c.loginErr = err
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// openSession restores the session of the account saved in the session cache, or logs in
+func (c *Client) openSession(ctx context.Context, username, password string) error {
+	if c.config.SessionCachePath != "" {
+		if token, ok := c.loadSession(ctx, username, password); ok {
+			logDebug(ctx, "Freenom session restored", map[string]interface{}{"username": username, "path": c.config.SessionCachePath})
+			c.setSession(username, password, token)
+			return nil
+		}
+	}
+
+	return c.login(ctx, username, password)
+}
+
+func (c *Client) login(ctx context.Context, username, password string) error {
+	body, err := c.do(ctx, http.MethodGet, c.clientAreaURL(), "", nil)
+
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	matches := reToken.FindSubmatch(body)
+	if len(matches) != 2 {
+		return fmt.Errorf("login: %w: token not found in login page", ErrParse)
+	}
+
+	token := string(matches[1])
+
+	params := url.Values{}
+	params.Add("token", token)
+	params.Add("username", username)
+	params.Add("password", password)
+
+	body, err = c.do(ctx, http.MethodPost, c.baseURL+"dologin.php", c.clientAreaURL(), params)
+
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	if !reLoggedIn.Match(body) {
+		return fmt.Errorf("login: %w", ErrAuthentication)
+	}
+
+	c.setSession(username, password, token)
+
+	if c.config.SessionCachePath != "" {
+		if err := c.saveSession(username, password, token); err != nil {
+			logWarn(ctx, "Freenom session not saved", map[string]interface{}{"path": c.config.SessionCachePath, "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// setSession stores the credentials and the token of a new session
+func (c *Client) setSession(username, password, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.username = username
+	c.password = password
+	c.loggedIn = true
+	c.token = token
+	c.logins++
+}
+
+// relogin opens a new session with the stored credentials, unless another
+// request already renewed the expired session
+func (c *Client) relogin(ctx context.Context, expiredSession uint64) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	c.mu.Lock()
+	username, password, session := c.username, c.password, c.logins
+	c.mu.Unlock()
+
+	if session != expiredSession {
+		return nil
+	}
+
+	logDebug(ctx, "Freenom session expired, logging in again", map[string]interface{}{"username": username})
+
+	return c.login(ctx, username, password)
+}
+
+// session returns the token of the current session and its number, logging in when there is none yet
+func (c *Client) session(ctx context.Context) (token string, session uint64, err error) {
+	c.mu.Lock()
+	loggedIn := c.loggedIn
+	c.mu.Unlock()
+
+	if !loggedIn {
+		if err := c.lazyLogin(ctx); err != nil {
+			return "", 0, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token, c.logins, nil
+}
+
+// authorized calls send with the token of the session.
+// When Freenom answers with a logged out page the session expired, so it logs in again
+// and calls send once more with the new token.
+func (c *Client) authorized(ctx context.Context, send func(token string) ([]byte, error)) ([]byte, error) {
+	token, session, err := c.session(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := send(token)
+
+	if err != nil || reLoggedIn.Match(body) {
+		return body, err
+	}
+
+	if err := c.relogin(ctx, session); err != nil {
+		// the error is an authentication error only when Freenom refused the credentials
+		return nil, fmt.Errorf("session expired and logging in again failed: %w", err)
+	}
+
+	token, _, err = c.session(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = send(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !reLoggedIn.Match(body) {
+		return nil, fmt.Errorf("session expired again after logging in: %w", ErrAuthentication)
+	}
+	return body, nil
+}
+
+// do sends a request to the client area and returns the response body.
+// When form is not nil it is sent url-encoded as the request body.
+// Requests failed for a transient error are retried up to MaxRetries times.
+// Canceling ctx aborts the request and the waits between the retries.
+func (c *Client) do(ctx context.Context, method, rawURL, referer string, form url.Values) ([]byte, error) {
+	return c.doMutation(ctx, method, rawURL, referer, form, nil)
+}
+
+// errApplied is returned by doMutation when the change failed but Freenom applied it anyway
+var errApplied = errors.New("change applied by Freenom")
+
+// doMutation sends a request changing the records of a domain, retrying the transient failures like do.
+// Freenom may have applied a change whose response failed, so before sending it again
+// applied re-reads the domain, and the change is not sent again once it is there.
+func (c *Client) doMutation(ctx context.Context, method, rawURL, referer string, form url.Values, applied func(ctx context.Context) (bool, error)) (body []byte, err error) {
+	ctx = c.logContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		body, err = c.doOnce(ctx, method, rawURL, referer, form)
+
+		if err == nil || ctx.Err() != nil || !isTransient(err) || attempt >= c.config.MaxRetries {
+			return
+		}
+
+		wait := c.backoff(attempt)
+		logDebug(ctx, "Freenom request failed, retrying", map[string]interface{}{"method": method, "url": rawURL, "wait": wait.String(), "error": err.Error()})
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		if applied == nil {
+			continue
+		}
+
+		done, checkErr := applied(ctx)
+
+		if checkErr != nil {
+			return nil, fmt.Errorf("%w, then checking whether Freenom applied the change failed: %s", err, checkErr)
+		}
+		if done {
+			logDebug(ctx, "Freenom applied the failed change, not sending it again", map[string]interface{}{"method": method, "url": rawURL})
+			return nil, errApplied
+		}
+	}
+}
+
+// sleep waits for d, returning early with the error of ctx when it is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff returns the wait before the retry following the given attempt.
+// It doubles at every attempt within RetryWaitMin and RetryWaitMax, and is jittered
+// between half and all of it, so concurrent requests do not retry at the same time.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.config.RetryWaitMin
+	for i := 0; i < attempt && wait < c.config.RetryWaitMax; i++ {
+		wait *= 2
+	}
+
+	if wait > c.config.RetryWaitMax {
+		wait = c.config.RetryWaitMax
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// isTransient reports whether the request may succeed when sent again:
+// timeouts, refused or reset connections, throttling and server errors.
+// Configuration errors, such as an untrusted certificate or an invalid url, fail right away.
+func isTransient(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode == http.StatusTooManyRequests || statusErr.statusCode >= 500
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	if errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certificateErr) || errors.As(err, &recordHeaderErr) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// every *url.Error is a net.Error, only its timeouts are transient
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (c *Client) doOnce(ctx context.Context, method, rawURL, referer string, form url.Values) ([]byte, error) {
+	var payload io.Reader
+	if form != nil {
+		payload = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if referer != "" {
+		req.Header.Add("Referer", referer)
+	}
+	if c.config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	if form != nil {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	}
+
+	if c.limiter != nil {
+		wait := c.limiter.reserve()
+		logDebug(ctx, "Freenom request waiting for the rate limit", map[string]interface{}{"method": method, "path": req.URL.Path, "wait": wait.String()})
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	logDebug(ctx, "Freenom request", map[string]interface{}{"method": method, "path": req.URL.Path, "status": res.StatusCode})
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &statusError{method: method, path: req.URL.Path, statusCode: res.StatusCode}
+	}
+
+	return io.ReadAll(res.Body)
+}
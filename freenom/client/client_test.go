@@ -0,0 +1,732 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testServer mimics the pages of the Freenom client area used by the client
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	password string
+	sessions int
+	// session is the value of the only valid session cookie, expiring it logs out every client
+	session string
+	logins  int
+	records []DomainRecord
+	// failures are the status codes answered to the next requests, before handling them
+	failures []int
+	requests int
+	// loginFailures are the status codes answered to the next requests of the login page of a logged out client
+	loginFailures []int
+	// maintenance answers the DNS management page with a page without its forms
+	maintenance bool
+	// failuresAfterAdd are the status codes answered to the next additions of records, after applying them
+	failuresAfterAdd []int
+	adds             int
+	// host and userAgent are the ones of the last request
+	host      string
+	userAgent string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{password: "secret"}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTLSTestServer(t *testing.T) *testServer {
+	s := &testServer{password: "secret"}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) expireSession() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.session = ""
+}
+
+func (s *testServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.host = r.Host
+	s.userAgent = r.UserAgent()
+
+	if len(s.failures) > 0 {
+		w.WriteHeader(s.failures[0])
+		s.failures = s.failures[1:]
+		return
+	}
+
+	if r.URL.Path == "/dologin.php" {
+		r.ParseForm()
+		if r.PostForm.Get("token") != "login-token" || r.PostForm.Get("password") != s.password {
+			fmt.Fprint(w, loginPage)
+			return
+		}
+
+		s.logins++
+		s.sessions++
+		s.session = "session-" + strconv.Itoa(s.sessions)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: s.session})
+		fmt.Fprint(w, homePage)
+		return
+	}
+
+	cookie, err := r.Cookie("session")
+	if err != nil || s.session == "" || cookie.Value != s.session {
+		if r.URL.RawQuery == "" && len(s.loginFailures) > 0 {
+			w.WriteHeader(s.loginFailures[0])
+			s.loginFailures = s.loginFailures[1:]
+			return
+		}
+		fmt.Fprint(w, loginPage)
+		return
+	}
+
+	switch {
+	case r.URL.Query().Get("action") == "domains":
+		fmt.Fprint(w, homePage+`<td class="second"><a href="#">example.tk </a></td><td class="third">2020-01-01</td><td class="fourth">2021-01-01</td><td><a href="clientarea.php?action=domaindetails&id=123">Manage</a></td>`)
+	case r.URL.Query().Get("managedns") == "example.tk":
+		if s.maintenance {
+			fmt.Fprint(w, homePage+`<h1>Down for maintenance</h1>`)
+			return
+		}
+		if r.Method == http.MethodPost && r.PostFormValue("dnsaction") == "add" {
+			s.adds++
+			for i := 0; r.PostForm.Get(fmt.Sprintf("addrecord[%d][type]", i)) != ""; i++ {
+				ttl, _ := strconv.Atoi(r.PostForm.Get(fmt.Sprintf("addrecord[%d][ttl]", i)))
+				s.records = append(s.records, DomainRecord{
+					Type:  r.PostForm.Get(fmt.Sprintf("addrecord[%d][type]", i)),
+					Name:  r.PostForm.Get(fmt.Sprintf("addrecord[%d][name]", i)),
+					TTL:   ttl,
+					Value: r.PostForm.Get(fmt.Sprintf("addrecord[%d][value]", i)),
+				})
+			}
+
+			if len(s.failuresAfterAdd) > 0 {
+				w.WriteHeader(s.failuresAfterAdd[0])
+				s.failuresAfterAdd = s.failuresAfterAdd[1:]
+				return
+			}
+			fmt.Fprint(w, homePage+dnsForm+`<li class="dnssuccess">Record added successfully</li>`)
+			return
+		}
+
+		if r.Method == http.MethodPost && r.PostFormValue("dnsaction") == "modify" {
+			var records []DomainRecord
+			for i := 0; r.PostForm.Get(fmt.Sprintf("records[%d][type]", i)) != ""; i++ {
+				ttl, _ := strconv.Atoi(r.PostForm.Get(fmt.Sprintf("records[%d][ttl]", i)))
+				records = append(records, DomainRecord{
+					Type:  r.PostForm.Get(fmt.Sprintf("records[%d][type]", i)),
+					Name:  r.PostForm.Get(fmt.Sprintf("records[%d][name]", i)),
+					TTL:   ttl,
+					Value: r.PostForm.Get(fmt.Sprintf("records[%d][value]", i)),
+				})
+			}
+			s.records = records
+			fmt.Fprint(w, homePage+dnsForm+`<li class="dnssuccess">Record modified successfully</li>`)
+			return
+		}
+
+		fmt.Fprint(w, homePage+dnsForm)
+		// the names and values are attributes of the page, escaped as HTML
+		for i, record := range s.records {
+			fmt.Fprintf(w, `<td><input name="records[%d][type]" value="%s"><input name="records[%d][name]" value="%s"><input name="records[%d][ttl]" value="%d"><input name="records[%d][value]" value="%s"></td>`,
+				i, record.Type, i, html.EscapeString(record.Name), i, record.TTL, i, html.EscapeString(record.Value))
+		}
+	default:
+		fmt.Fprint(w, homePage)
+	}
+}
+
+const loginPage = `<form class="form-stacked" action="dologin.php"><input type="hidden" name="token" value="login-token"></form>`
+const homePage = `<span class="hidden-sm">Hello Tester</span>`
+const dnsForm = `<form id="recordsaddform"><input type="hidden" name="dnsaction" value="add"></form>`
+
+func newTestClient(t *testing.T, server *testServer) *Client {
+	c, err := New(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return c
+}
+
+func TestLoginAndGetDomainInfo(t *testing.T) {
+	server := newTestServer(t)
+	server.records = []DomainRecord{{Type: "A", Name: "WWW", TTL: 3600, Value: "10.10.10.10"}}
+
+	c := newTestClient(t, server)
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	info, err := c.GetDomainInfo(context.Background(), "example.tk")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if info.DomainID != "123" || len(info.Records) != 1 || *info.Records[0] != server.records[0] {
+		t.Errorf("unexpected domain info %+v", info)
+	}
+}
+
+func TestGetDomainInfoEscapedValues(t *testing.T) {
+	server := newTestServer(t)
+	server.records = []DomainRecord{{Type: "TXT", Name: "", TTL: 3600, Value: `"v=spf1 include:a.example.com&b <all>"`}}
+
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	info, err := c.GetDomainInfo(context.Background(), "example.tk")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(info.Records) != 1 || *info.Records[0] != server.records[0] {
+		t.Errorf("expected the value to be unescaped, got %+v", info.Records)
+	}
+}
+
+func TestModifyRecordKeepsEscapedValues(t *testing.T) {
+	server := newTestServer(t)
+	server.records = []DomainRecord{
+		{Type: "TXT", Name: "", TTL: 3600, Value: `"v=spf1 -all"`},
+		{Type: "A", Name: "www", TTL: 3600, Value: "10.10.10.10"},
+	}
+
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	info, err := c.GetDomainInfo(context.Background(), "example.tk")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	newRecord := &DomainRecord{Type: "A", Name: "www", TTL: 3600, Value: "10.10.10.11"}
+	if err := c.ModifyRecord(context.Background(), "example.tk", info.Records[1], newRecord); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.records[0].Value != `"v=spf1 -all"` || server.records[1] != *newRecord {
+		t.Errorf("expected the other records to be sent back unchanged, got %+v", server.records)
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	err := c.Login(context.Background(), "user@example.com", "wrong")
+
+	if !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+}
+
+func TestReloginWhenSessionExpired(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.expireSession()
+
+	if _, err := c.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+		t.Fatalf("expected the client to log in again, got %s", err)
+	}
+
+	if server.logins != 2 {
+		t.Errorf("expected 2 logins, got %d", server.logins)
+	}
+}
+
+func TestReloginUnavailable(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 0}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.expireSession()
+	server.loginFailures = []int{http.StatusServiceUnavailable}
+
+	_, err := c.GetDomainInfo(context.Background(), "example.tk")
+
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.statusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the status of the failed login to be kept, got %v", err)
+	}
+	if errors.Is(err, ErrAuthentication) {
+		t.Errorf("expected an unavailable Freenom not to be an authentication error, got %v", err)
+	}
+}
+
+func TestReloginOnlyOnceForConcurrentRequests(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.ListDomains(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.expireSession()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if server.logins != 2 {
+		t.Errorf("expected a single login after the expiration, got %d logins", server.logins-1)
+	}
+}
+
+func TestReloginFailure(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.expireSession()
+	server.password = "changed"
+
+	_, err := c.GetDomainInfo(context.Background(), "example.tk")
+
+	if !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "session expired") {
+		t.Errorf("expected the error to explain the session expired, got %s", err)
+	}
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 3, RetryWaitMin: time.Millisecond, RetryWaitMax: 2 * time.Millisecond}
+
+	server.failures = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("expected the transient failures to be retried, got %s", err)
+	}
+
+	if server.requests != 4 {
+		t.Errorf("expected 4 requests, got %d", server.requests)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 2, RetryWaitMin: time.Millisecond, RetryWaitMax: 2 * time.Millisecond}
+
+	server.failures = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err == nil {
+		t.Fatalf("expected an error after the retries")
+	}
+
+	if server.requests != 3 {
+		t.Errorf("expected 3 requests, got %d", server.requests)
+	}
+}
+
+func TestRateLimitedError(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 1, RetryWaitMin: time.Millisecond, RetryWaitMax: 2 * time.Millisecond}
+
+	server.failures = []int{http.StatusTooManyRequests, http.StatusTooManyRequests}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected a rate limited error, got %v", err)
+	}
+}
+
+func TestNoRetryForPermanentFailures(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 3, RetryWaitMin: time.Millisecond, RetryWaitMax: 2 * time.Millisecond}
+
+	server.failures = []int{http.StatusForbidden}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if server.requests != 1 {
+		t.Errorf("expected a single request, got %d", server.requests)
+	}
+
+	// wrong credentials are not retried either
+	server.requests = 0
+
+	if err := c.Login(context.Background(), "user@example.com", "wrong"); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+
+	if server.requests != 2 {
+		t.Errorf("expected only the 2 login requests, got %d", server.requests)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{config: Config{RetryWaitMin: time.Second, RetryWaitMax: 10 * time.Second}}
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		wait := c.backoff(attempt)
+
+		if wait < max/2 || wait > max {
+			t.Errorf("attempt %d: expected a wait between %s and %s, got %s", attempt, max/2, max, wait)
+		}
+	}
+}
+
+func TestRateLimitIncludesLogin(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.limiter = newRateLimiter(60)
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if c.limiter.tokens > maxBurst-2+0.1 {
+		t.Errorf("expected the 2 login requests to take a token each, %f tokens left", c.limiter.tokens)
+	}
+}
+
+func TestCancelAbortsRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	c, err := New(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = c.Login(ctx, "user@example.com", "secret")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to abort the login, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the login to be aborted right away, took %s", elapsed)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	server := newTestServer(t)
+
+	c, err := New(Config{BaseURL: server.URL, UserAgent: "terraform-provider-freenom/test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.userAgent != "terraform-provider-freenom/test" {
+		t.Errorf("expected the configured user agent, got %q", server.userAgent)
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	proxy := newTestServer(t)
+
+	c, err := New(Config{BaseURL: "http://freenom.invalid", ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("expected the login to go through the proxy, got %s", err)
+	}
+
+	if proxy.host != "freenom.invalid" {
+		t.Errorf("expected the proxy to receive the requests for freenom.invalid, got %q", proxy.host)
+	}
+}
+
+func TestCACertFile(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	if err := os.WriteFile(caCertFile, caCert, 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := map[string]struct {
+		config  Config
+		success bool
+	}{
+		"untrusted":            {config: Config{BaseURL: server.URL}},
+		"ca_cert_file":         {config: Config{BaseURL: server.URL, CACertFile: caCertFile}, success: true},
+		"insecure_skip_verify": {config: Config{BaseURL: server.URL, InsecureSkipVerify: true}, success: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New(test.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = c.Login(context.Background(), "user@example.com", "secret")
+
+			if test.success && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !test.success && err == nil {
+				t.Errorf("expected the certificate of the server to be refused")
+			}
+		})
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := map[string]Config{
+		"base_url":        {BaseURL: "my.freenom.com"},
+		"proxy_url":       {ProxyURL: "://proxy"},
+		"ca_cert_file":    {CACertFile: notPEM},
+		"missing_ca_cert": {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+	}
+
+	for name, config := range tests {
+		if _, err := New(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLazyLoginOnlyOnce(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	c.SetCredentials("user@example.com", "secret")
+
+	if server.requests != 0 {
+		t.Fatalf("expected no request before the client is used, got %d", server.requests)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if server.logins != 1 {
+		t.Errorf("expected a single login, got %d", server.logins)
+	}
+}
+
+func TestLazyLoginRefusedOnce(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+
+	c.SetCredentials("user@example.com", "wrong")
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetDomainInfo(context.Background(), "example.tk"); !errors.Is(err, ErrAuthentication) {
+			t.Fatalf("expected an authentication error, got %v", err)
+		}
+	}
+
+	if server.requests != 2 {
+		t.Errorf("expected the refused credentials to be tried once, got %d requests", server.requests)
+	}
+}
+
+func TestDomainNotFoundError(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	if _, err := c.GetDomainInfo(context.Background(), "missing.tk"); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("expected a domain not found error, got %v", err)
+	}
+}
+
+func TestModifyMissingRecord(t *testing.T) {
+	server := newTestServer(t)
+	server.records = []DomainRecord{{Type: "A", Name: "WWW", TTL: 3600, Value: "10.10.10.10"}}
+
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	oldRecord := &DomainRecord{Type: "A", Name: "WWW", TTL: 3600, Value: "10.10.10.11"}
+	newRecord := &DomainRecord{Type: "A", Name: "WWW", TTL: 300, Value: "10.10.10.11"}
+
+	if err := c.ModifyRecord(context.Background(), "example.tk", oldRecord, newRecord); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("expected a record not found error, got %v", err)
+	}
+}
+
+func TestGetDomainInfoUnrecognizedPage(t *testing.T) {
+	server := newTestServer(t)
+	server.maintenance = true
+
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	if _, err := c.GetDomainInfo(context.Background(), "example.tk"); !errors.Is(err, ErrParse) {
+		t.Fatalf("expected a parse error for a page without the DNS management form, got %v", err)
+	}
+}
+
+func TestGetDomainInfoWithoutRecords(t *testing.T) {
+	server := newTestServer(t)
+
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	info, err := c.GetDomainInfo(context.Background(), "example.tk")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(info.Records) != 0 {
+		t.Errorf("expected no records, got %+v", info.Records)
+	}
+}
+
+func TestNoRetryUntrustedCertificate(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	c, err := New(Config{BaseURL: server.URL, MaxRetries: 4, RetryWaitMin: time.Second, RetryWaitMax: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	start := time.Now()
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err == nil {
+		t.Fatalf("expected the certificate of the server to be refused")
+	}
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected the certificate error to fail right away, took %s", elapsed)
+	}
+}
+
+// timeoutError is a network error reporting a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		transient bool
+	}{
+		"timeout":            {err: &url.Error{Op: "Get", URL: "https://my.freenom.com/", Err: timeoutError{}}, transient: true},
+		"connection refused": {err: &url.Error{Op: "Get", URL: "https://my.freenom.com/", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, transient: true},
+		"connection reset":   {err: &url.Error{Op: "Get", URL: "https://my.freenom.com/", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, transient: true},
+		"429":                {err: &statusError{statusCode: http.StatusTooManyRequests}, transient: true},
+		"503":                {err: &statusError{statusCode: http.StatusServiceUnavailable}, transient: true},
+		"404":                {err: &statusError{statusCode: http.StatusNotFound}},
+		"unknown authority":  {err: &url.Error{Op: "Get", URL: "https://my.freenom.com/", Err: x509.UnknownAuthorityError{}}},
+		"unsupported scheme": {err: &url.Error{Op: "Get", URL: "ftp://my.freenom.com/", Err: errors.New("unsupported protocol scheme \"ftp\"")}},
+	}
+
+	for name, test := range tests {
+		if transient := isTransient(test.err); transient != test.transient {
+			t.Errorf("%s: expected transient %v, got %v", name, test.transient, transient)
+		}
+	}
+}
+
+func TestAddRecordNotSentAgainWhenApplied(t *testing.T) {
+	server := newTestServer(t)
+	server.failuresAfterAdd = []int{http.StatusBadGateway}
+
+	c := newTestClient(t, server)
+	c.config = Config{MaxRetries: 3, RetryWaitMin: time.Millisecond, RetryWaitMax: 2 * time.Millisecond}
+	c.SetCredentials("user@example.com", "secret")
+
+	err := c.AddRecord(context.Background(), "example.tk", []DomainRecord{{Type: "A", Name: "www", TTL: 3600, Value: "10.10.10.10"}})
+	if err != nil {
+		t.Fatalf("expected the applied addition to succeed, got %s", err)
+	}
+
+	if server.adds != 1 || len(server.records) != 1 {
+		t.Errorf("expected the addition to be sent once, got %d additions and records %+v", server.adds, server.records)
+	}
+}
+
+func TestDnsError(t *testing.T) {
+	if err := dnsError("There is already a record with this name and value"); !errors.Is(err, ErrDuplicateRecord) {
+		t.Errorf("expected a duplicate record error, got %v", err)
+	}
+
+	if err := dnsError("Invalid value"); errors.Is(err, ErrDuplicateRecord) {
+		t.Errorf("expected a generic error, got %v", err)
+	}
+}
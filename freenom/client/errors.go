@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// ErrAuthentication is returned when Freenom refuses the credentials,
+// including when the session expired and logging in again failed
+var ErrAuthentication = errors.New("authentication failed")
+
+// ErrRateLimited is returned when Freenom keeps throttling the requests after the retries
+var ErrRateLimited = errors.New("rate limited by Freenom")
+
+// ErrDomainNotFound is returned when the domain is not registered in the account
+var ErrDomainNotFound = errors.New("domain not found in the account")
+
+// ErrRecordNotFound is returned when the record is not in the domain
+var ErrRecordNotFound = errors.New("record not found")
+
+// ErrDuplicateRecord is returned when the domain already has a record with the same name, type and value
+var ErrDuplicateRecord = errors.New("record already exists")
+
+// ErrParse is returned when a page of Freenom does not have the expected content,
+// likely because Freenom changed it
+var ErrParse = errors.New("unexpected Freenom page")
+
+// reDuplicateMessage matches the errors of Freenom about an existing record
+var reDuplicateMessage = regexp.MustCompile(`(?i)already (exists|a record)|duplicate`)
+
+// dnsError returns the error for a DNS change Freenom rejected with message
+func dnsError(message string) error {
+	if reDuplicateMessage.MatchString(message) {
+		return fmt.Errorf("%w: %s", ErrDuplicateRecord, message)
+	}
+	return fmt.Errorf("rejected by Freenom: %s", message)
+}
+
+// statusError is returned when Freenom answers with an unexpected status code
+type statusError struct {
+	method     string
+	path       string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status code %d", e.method, e.path, e.statusCode)
+}
+
+// Is makes the throttled requests match ErrRateLimited
+func (e *statusError) Is(target error) bool {
+	return target == ErrRateLimited && e.statusCode == http.StatusTooManyRequests
+}
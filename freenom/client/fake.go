@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Fake is an in-memory replacement of Client that stores domains and records.
+// It lets resources and data sources be tested without a Freenom account.
+type Fake struct {
+	mu      sync.Mutex
+	domains map[string][]*DomainRecord
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		domains: make(map[string][]*DomainRecord),
+	}
+}
+
+// AddDomain registers the domain in the fake account with the given records
+func (f *Fake) AddDomain(domain string, records ...DomainRecord) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.domains[domain] = nil
+	for i := range records {
+		record := records[i]
+		f.domains[domain] = append(f.domains[domain], &record)
+	}
+}
+
+// Records returns a copy of the records of the domain
+func (f *Fake) Records(domain string) []DomainRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var records []DomainRecord
+	for _, record := range f.domains[domain] {
+		records = append(records, *record)
+	}
+	return records
+}
+
+func (f *Fake) GetDomainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, ok := f.domains[domain]
+	if !ok {
+		return nil, fmt.Errorf("domain %s: %w", domain, ErrDomainNotFound)
+	}
+
+	info := &DomainInfo{
+		Domain:   domain,
+		DomainID: domain,
+	}
+	for _, record := range records {
+		copied := *record
+		info.Records = append(info.Records, &copied)
+	}
+	return info, nil
+}
+
+func (f *Fake) AddRecord(ctx context.Context, domain string, records []DomainRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(records) == 0 {
+		return fmt.Errorf("empty records")
+	}
+
+	existing, ok := f.domains[domain]
+	if !ok {
+		return fmt.Errorf("domain %s: %w", domain, ErrDomainNotFound)
+	}
+
+	for i := range records {
+		record := records[i]
+		record.Type = strings.ToUpper(record.Type)
+		if !strings.EqualFold(record.Type, RecordTypeMX) {
+			record.Priority = 0
+		}
+
+		for _, r := range existing {
+			if strings.EqualFold(r.Type, record.Type) && strings.EqualFold(r.Name, record.Name) && r.Value == record.Value {
+				return fmt.Errorf("%w: there is already a record with the same name, type and value", ErrDuplicateRecord)
+			}
+		}
+		existing = append(existing, &record)
+	}
+
+	f.domains[domain] = existing
+	return nil
+}
+
+func (f *Fake) ModifyRecord(ctx context.Context, domain string, oldRecord, newRecord *DomainRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, record := range f.domains[domain] {
+		if sameRecord(record, oldRecord) {
+			modified := *newRecord
+			f.domains[domain][i] = &modified
+			return nil
+		}
+	}
+	return fmt.Errorf("%w in %s", ErrRecordNotFound, domain)
+}
+
+func (f *Fake) DeleteRecord(ctx context.Context, domain string, record *DomainRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records := f.domains[domain]
+	for i, r := range records {
+		if sameRecord(r, record) {
+			f.domains[domain] = append(records[:i:i], records[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w in %s", ErrRecordNotFound, domain)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem of the provider logs, enabled with TF_LOG_PROVIDER_FREENOM
+const LogSubsystem = "freenom"
+
+// secretFields are the log fields whose values are always masked
+var secretFields = []string{"password", "token", "cookie", "session"}
+
+// NewLogContext returns ctx with the freenom logging subsystem and the given fields.
+// The values of the secret fields are masked in every log.
+func NewLogContext(ctx context.Context, fields map[string]interface{}) context.Context {
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, LogSubsystem, secretFields...)
+
+	for key, value := range fields {
+		ctx = tflog.SubsystemSetField(ctx, LogSubsystem, key, value)
+	}
+	return ctx
+}
+
+// logContext masks the password and the session cookies of the client wherever they appear in the logs
+func (c *Client) logContext(ctx context.Context) context.Context {
+	secrets := c.secrets()
+
+	if len(secrets) == 0 {
+		return ctx
+	}
+
+	ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, secrets...)
+	return tflog.SubsystemMaskMessageStrings(ctx, LogSubsystem, secrets...)
+}
+
+// secrets returns the password, the session token and the session cookies of the client
+func (c *Client) secrets() []string {
+	c.mu.Lock()
+	candidates := []string{c.password, c.token}
+	c.mu.Unlock()
+
+	if u, err := url.Parse(c.baseURL); err == nil {
+		for _, cookie := range c.httpClient.Jar.Cookies(u) {
+			candidates = append(candidates, cookie.Value)
+		}
+	}
+
+	var secrets []string
+	for _, secret := range candidates {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+func logDebug(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.SubsystemDebug(ctx, LogSubsystem, msg, fields)
+}
+
+func logWarn(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.SubsystemWarn(ctx, LogSubsystem, msg, fields)
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLogsMaskSecrets(t *testing.T) {
+	server := newTestServer(t)
+	c := newTestClient(t, server)
+	c.SetCredentials("user@example.com", "secret")
+
+	var output bytes.Buffer
+	ctx := NewLogContext(tflogtest.RootLogger(context.Background(), &output), map[string]interface{}{"operation": "read"})
+
+	if _, err := c.GetDomainInfo(ctx, "example.tk"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	logDebug(c.logContext(ctx), "logging in with secret", map[string]interface{}{
+		"password": "secret",
+		"login":    "token login-token, cookie " + server.session,
+	})
+
+	logs := output.String()
+
+	if !strings.Contains(logs, `"@module":"provider.freenom"`) || !strings.Contains(logs, `"operation":"read"`) {
+		t.Errorf("expected the logs of the freenom subsystem with the operation, got %s", logs)
+	}
+
+	for _, secret := range []string{"secret", "login-token", server.session} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to be masked, got %s", secret, logs)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+// Record types supported by Freenom
+const (
+	RecordTypeA     string = "A"
+	RecordTypeAAAA  string = "AAAA"
+	RecordTypeCNAME string = "CNAME"
+	RecordTypeLOC   string = "LOC"
+	RecordTypeMX    string = "MX"
+	RecordTypeNAPTR string = "NAPTR"
+	RecordTypeRP    string = "RP"
+	RecordTypeTXT   string = "TXT"
+)
+
+// DomainRecord is a single DNS record of a domain
+type DomainRecord struct {
+	Type     string
+	Name     string
+	TTL      int
+	Value    string
+	Priority int
+}
+
+// DomainInfo describes a domain of the account and its DNS records
+type DomainInfo struct {
+	Domain   string
+	DomainID string
+	RegDate  string
+	ExpDate  string
+	Records  []*DomainRecord
+}
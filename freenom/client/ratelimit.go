@@ -0,0 +1,57 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// maxBurst is the most requests the rate limiter lets through at once after being idle
+const maxBurst = 5
+
+// rateLimiter is a token bucket refilled with requestsPerMinute tokens per minute.
+// Every request takes a token, waiting for it when the bucket is empty.
+type rateLimiter struct {
+	interval time.Duration
+	burst    float64
+	now      func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerMinute int) *rateLimiter {
+	burst := maxBurst
+	if requestsPerMinute < burst {
+		burst = requestsPerMinute
+	}
+
+	return &rateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		burst:    float64(burst),
+		now:      time.Now,
+		tokens:   float64(burst),
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	// the token may be borrowed from the future, so concurrent requests queue up in order
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
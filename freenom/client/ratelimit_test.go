@@ -0,0 +1,53 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+
+	l := newRateLimiter(60)
+	l.now = func() time.Time { return now }
+
+	// the full bucket lets a burst through
+	for i := 0; i < maxBurst; i++ {
+		if wait := l.reserve(); wait != 0 {
+			t.Fatalf("request %d: expected no wait, got %s", i, wait)
+		}
+	}
+
+	// then the requests queue up one second apart
+	for i := 1; i <= 3; i++ {
+		if wait := l.reserve(); wait != time.Duration(i)*time.Second {
+			t.Fatalf("expected a wait of %ds, got %s", i, wait)
+		}
+	}
+
+	// after being idle the bucket is refilled up to the burst
+	now = now.Add(time.Hour)
+
+	for i := 0; i < maxBurst; i++ {
+		if wait := l.reserve(); wait != 0 {
+			t.Fatalf("request %d: expected no wait after being idle, got %s", i, wait)
+		}
+	}
+	if wait := l.reserve(); wait != time.Second {
+		t.Fatalf("expected a wait of 1s, got %s", wait)
+	}
+}
+
+func TestRateLimiterSlowRate(t *testing.T) {
+	now := time.Now()
+
+	l := newRateLimiter(2)
+	l.now = func() time.Time { return now }
+
+	l.reserve()
+	l.reserve()
+
+	if wait := l.reserve(); wait != 30*time.Second {
+		t.Fatalf("expected a wait of 30s, got %s", wait)
+	}
+}
@@ -0,0 +1,366 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var reDomains = regexp.MustCompile(`(?is:class="second"><[^>]+?>(.+?)\s+.+?class="third">(\d{4}-\d{2}-\d{2}).+?class="fourth">(\d{4}-\d{2}-\d{2}).+?id=(\d+?)")`)
+var reRecords = regexp.MustCompile(`(?is:records\[\d+\]\[type\]" value="([^"]*)".+?records\[\d+\]\[name\]" value="([^"]*)".+?records\[\d+\]\[ttl\]" value="(\d+)".+?records\[\d+\]\[value\]" value="([^"]*)".+?(?:records\[\d+\]\[priority\]" value="(\d+)".+?)?</td>)`)
+var reDnsError = regexp.MustCompile(`(?is:class="dnserror">(.+?)</li>)`)
+var reDnsSuccess = regexp.MustCompile(`(?is:class="dnssuccess")`)
+
+// reDnsForm matches the forms of the DNS management page, which a domain without records has too
+var reDnsForm = regexp.MustCompile(`(?is:name="dnsaction")`)
+
+// ListDomains returns all the domains of the account
+func (c *Client) ListDomains(ctx context.Context) (domains map[string]*DomainInfo, err error) {
+	body, err := c.authorized(ctx, func(string) ([]byte, error) {
+		return c.do(ctx, http.MethodGet, c.clientAreaURL()+"?action=domains", c.clientAreaURL(), nil)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	domains = make(map[string]*DomainInfo)
+
+	for _, match := range reDomains.FindAllSubmatch(body, -1) {
+		domain := string(match[1])
+
+		info, ok := c.domains[domain]
+		if !ok {
+			// keep the cached records of already known domains
+			info = &DomainInfo{Domain: domain}
+			c.domains[domain] = info
+		}
+
+		info.DomainID = string(match[4])
+		info.RegDate = string(match[2])
+		info.ExpDate = string(match[3])
+
+		domains[domain] = info
+	}
+	return
+}
+
+// GetDomainInfo returns the domain and all its DNS records
+func (c *Client) GetDomainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	info, err := c.domainInfo(ctx, domain)
+
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("managedns", domain)
+	params.Add("domainid", info.DomainID)
+
+	body, err := c.authorized(ctx, func(string) ([]byte, error) {
+		return c.do(ctx, http.MethodGet, c.clientAreaURL()+"?"+params.Encode(), c.clientAreaURL(), nil)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("reading domain %s: %w", domain, err)
+	}
+
+	// a changed or maintenance page would otherwise read as a domain without records
+	if !reDnsForm.Match(body) {
+		return nil, fmt.Errorf("reading domain %s: %w: no DNS management form in the page", domain, ErrParse)
+	}
+
+	var records []*DomainRecord
+
+	for _, match := range reRecords.FindAllSubmatch(body, -1) {
+		ttl, err := strconv.Atoi(string(match[3]))
+
+		if err != nil {
+			return nil, fmt.Errorf("reading domain %s: %w: invalid ttl %q", domain, ErrParse, match[3])
+		}
+
+		priority := 0
+		if len(match[5]) > 0 {
+			priority, err = strconv.Atoi(string(match[5]))
+
+			if err != nil {
+				return nil, fmt.Errorf("reading domain %s: %w: invalid priority %q", domain, ErrParse, match[5])
+			}
+		}
+
+		// the names and values are attributes of the page, so their quotes, ampersands and brackets are escaped
+		records = append(records, &DomainRecord{
+			Type:     html.UnescapeString(string(match[1])),
+			Name:     html.UnescapeString(string(match[2])),
+			TTL:      ttl,
+			Value:    html.UnescapeString(string(match[4])),
+			Priority: priority,
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info.Records = records
+
+	return &DomainInfo{
+		Domain:   info.Domain,
+		DomainID: info.DomainID,
+		RegDate:  info.RegDate,
+		ExpDate:  info.ExpDate,
+		Records:  records,
+	}, nil
+}
+
+// AddRecord adds the records to the domain
+func (c *Client) AddRecord(ctx context.Context, domain string, records []DomainRecord) error {
+	if len(records) == 0 {
+		return fmt.Errorf("empty records")
+	}
+
+	info, err := c.domainInfo(ctx, domain)
+
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("dnsaction", "add")
+
+	for i, record := range records {
+		params.Add(fmt.Sprintf("addrecord[%d][name]", i), record.Name)
+		params.Add(fmt.Sprintf("addrecord[%d][type]", i), strings.ToUpper(record.Type))
+		params.Add(fmt.Sprintf("addrecord[%d][ttl]", i), strconv.Itoa(record.TTL))
+		params.Add(fmt.Sprintf("addrecord[%d][value]", i), record.Value)
+		params.Add(fmt.Sprintf("addrecord[%d][priority]", i), formatPriority(&record))
+		params.Add(fmt.Sprintf("addrecord[%d][port]", i), "")
+		params.Add(fmt.Sprintf("addrecord[%d][weight]", i), "")
+		params.Add(fmt.Sprintf("addrecord[%d][forward_type]", i), "1")
+	}
+
+	c.mu.Lock()
+	before := info.Records
+	c.mu.Unlock()
+
+	// the records are added once they are all there, and were not there before
+	applied := func(current []*DomainRecord) bool {
+		for i := range records {
+			if !hasRecord(current, &records[i]) || hasRecord(before, &records[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := c.postDnsAction(ctx, info, params, applied); err != nil {
+		return fmt.Errorf("adding records to %s: %w", domain, err)
+	}
+	return nil
+}
+
+// ModifyRecord replaces oldRecord with newRecord.
+// Freenom expects all the records of the domain, so the ones known from the last GetDomainInfo are sent back unchanged.
+func (c *Client) ModifyRecord(ctx context.Context, domain string, oldRecord, newRecord *DomainRecord) error {
+	info, err := c.domainInfo(ctx, domain)
+
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("dnsaction", "modify")
+
+	c.mu.Lock()
+	records := info.Records
+	c.mu.Unlock()
+
+	found := false
+	for i, record := range records {
+		if sameRecord(record, oldRecord) {
+			record = newRecord
+			found = true
+		}
+
+		params.Add(fmt.Sprintf("records[%d][line]", i), "")
+		params.Add(fmt.Sprintf("records[%d][type]", i), strings.ToUpper(record.Type))
+		params.Add(fmt.Sprintf("records[%d][name]", i), record.Name)
+		params.Add(fmt.Sprintf("records[%d][ttl]", i), strconv.Itoa(record.TTL))
+		params.Add(fmt.Sprintf("records[%d][value]", i), record.Value)
+		params.Add(fmt.Sprintf("records[%d][priority]", i), formatPriority(record))
+	}
+
+	if !found {
+		return fmt.Errorf("modifying record of %s: %w", domain, ErrRecordNotFound)
+	}
+
+	applied := func(current []*DomainRecord) bool {
+		if sameRecord(oldRecord, newRecord) {
+			return false
+		}
+
+		hasOld, hasNew := false, false
+		for _, record := range current {
+			hasOld = hasOld || sameRecord(record, oldRecord)
+			hasNew = hasNew || sameRecord(record, newRecord)
+		}
+		return hasNew && !hasOld
+	}
+
+	if err := c.postDnsAction(ctx, info, params, applied); err != nil {
+		return fmt.Errorf("modifying record of %s: %w", domain, err)
+	}
+	return nil
+}
+
+// DeleteRecord removes the record from the domain
+func (c *Client) DeleteRecord(ctx context.Context, domain string, record *DomainRecord) error {
+	info, err := c.domainInfo(ctx, domain)
+
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("managedns", domain)
+	params.Add("domainid", info.DomainID)
+	params.Add("dnsaction", "delete")
+	params.Add("records", record.Type)
+	params.Add("name", record.Name)
+	params.Add("value", record.Value)
+	params.Add("line", "")
+	params.Add("ttl", strconv.Itoa(record.TTL))
+	params.Add("priority", formatPriority(record))
+	params.Add("weight", "")
+	params.Add("port", "")
+	params.Add("page", "")
+
+	applied := c.appliedCheck(domain, func(current []*DomainRecord) bool {
+		return !hasRecord(current, record)
+	})
+
+	body, err := c.authorized(ctx, func(string) ([]byte, error) {
+		return c.doMutation(ctx, http.MethodGet, c.clientAreaURL()+"?"+params.Encode(), c.clientAreaURL(), nil, applied)
+	})
+
+	if errors.Is(err, errApplied) {
+		c.GetDomainInfo(ctx, domain) // refresh the cached records
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleting record of %s: %w", domain, err)
+	}
+
+	if matches := reDnsError.FindSubmatch(body); len(matches) == 2 {
+		return fmt.Errorf("deleting record of %s: %w", domain, dnsError(string(matches[1])))
+	}
+	if !reDnsSuccess.Match(body) {
+		return fmt.Errorf("deleting record of %s: %w: no success message in the response", domain, ErrParse)
+	}
+
+	c.GetDomainInfo(ctx, domain) // refresh the cached records
+	return nil
+}
+
+// domainInfo returns the cached domain, listing the domains of the account when it is not known yet
+func (c *Client) domainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	c.mu.Lock()
+	info, ok := c.domains[domain]
+	c.mu.Unlock()
+
+	if ok {
+		return info, nil
+	}
+
+	domains, err := c.ListDomains(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info, ok = domains[domain]; !ok {
+		return nil, fmt.Errorf("domain %s: %w", domain, ErrDomainNotFound)
+	}
+	return info, nil
+}
+
+// postDnsAction sends the DNS action in params with the token of the session,
+// applied reports whether the records of the domain show the action as done
+func (c *Client) postDnsAction(ctx context.Context, info *DomainInfo, params url.Values, applied func(current []*DomainRecord) bool) error {
+	query := url.Values{}
+	query.Add("managedns", info.Domain)
+	query.Add("domainid", info.DomainID)
+
+	pageURL := c.clientAreaURL() + "?" + query.Encode()
+
+	check := c.appliedCheck(info.Domain, applied)
+
+	body, err := c.authorized(ctx, func(token string) ([]byte, error) {
+		params.Set("token", token)
+		return c.doMutation(ctx, http.MethodPost, pageURL, pageURL, params, check)
+	})
+
+	if errors.Is(err, errApplied) {
+		c.GetDomainInfo(ctx, info.Domain) // refresh the cached records
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !reDnsSuccess.Match(body) {
+		if matches := reDnsError.FindSubmatch(body); len(matches) == 2 {
+			return dnsError(string(matches[1]))
+		}
+		return fmt.Errorf("%w: no success message in the response", ErrParse)
+	}
+
+	c.GetDomainInfo(ctx, info.Domain) // refresh the cached records
+	return nil
+}
+
+// appliedCheck returns the check of doMutation re-reading the records of the domain
+func (c *Client) appliedCheck(domain string, applied func(current []*DomainRecord) bool) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		info, err := c.GetDomainInfo(ctx, domain)
+
+		if err != nil {
+			return false, err
+		}
+		return applied(info.Records), nil
+	}
+}
+
+// hasRecord reports whether records has a record with the type, name and value of record
+func hasRecord(records []*DomainRecord, record *DomainRecord) bool {
+	for _, r := range records {
+		if strings.EqualFold(r.Type, record.Type) && strings.EqualFold(r.Name, record.Name) && r.Value == record.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPriority returns the priority as expected by Freenom, which only keeps it for MX records
+func formatPriority(record *DomainRecord) string {
+	if strings.EqualFold(record.Type, RecordTypeMX) {
+		return strconv.Itoa(record.Priority)
+	}
+	return ""
+}
+
+func sameRecord(a, b *DomainRecord) bool {
+	return strings.EqualFold(a.Type, b.Type) &&
+		strings.EqualFold(a.Name, b.Name) &&
+		strings.EqualFold(a.Value, b.Value) &&
+		a.TTL == b.TTL &&
+		a.Priority == b.Priority
+}
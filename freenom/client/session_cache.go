@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+// savedSession is the session of an account saved to the session cache file
+type savedSession struct {
+	BaseURL string        `json:"base_url"`
+	Token   string        `json:"token"`
+	Cookies []savedCookie `json:"cookies"`
+}
+
+type savedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// loadSession restores the session saved in the session cache file for the account.
+// It returns the token of the session, or false when there is no usable session:
+// a missing file, a file saved for other credentials or another base url.
+func (c *Client) loadSession(ctx context.Context, username, password string) (string, bool) {
+	data, err := os.ReadFile(c.config.SessionCachePath)
+
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn(ctx, "Freenom session cache not readable", map[string]interface{}{"path": c.config.SessionCachePath, "error": err.Error()})
+		}
+		return "", false
+	}
+
+	plaintext, err := decryptSession(data, username, password)
+
+	if err != nil {
+		logDebug(ctx, "Freenom session cache not usable, logging in", map[string]interface{}{"path": c.config.SessionCachePath, "error": err.Error()})
+		return "", false
+	}
+
+	var session savedSession
+	if err := json.Unmarshal(plaintext, &session); err != nil || session.BaseURL != c.baseURL {
+		return "", false
+	}
+
+	u, err := url.Parse(c.baseURL)
+
+	if err != nil {
+		return "", false
+	}
+
+	cookies := make([]*http.Cookie, 0, len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	c.httpClient.Jar.SetCookies(u, cookies)
+
+	return session.Token, true
+}
+
+// saveSession writes the cookies of the session to the session cache file, encrypted with the credentials
+func (c *Client) saveSession(username, password, token string) error {
+	u, err := url.Parse(c.baseURL)
+
+	if err != nil {
+		return err
+	}
+
+	session := savedSession{BaseURL: c.baseURL, Token: token}
+	for _, cookie := range c.httpClient.Jar.Cookies(u) {
+		session.Cookies = append(session.Cookies, savedCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	plaintext, err := json.Marshal(session)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := encryptSession(plaintext, username, password)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.config.SessionCachePath), 0700); err != nil {
+		return err
+	}
+
+	// write a temporary file and rename it, so a concurrent run never reads half a file
+	tmp, err := os.CreateTemp(filepath.Dir(c.config.SessionCachePath), ".freenom-session-*")
+
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.config.SessionCachePath)
+}
+
+// encryptSession encrypts the session with AES-GCM and a key derived from the credentials with scrypt.
+// The random salt and nonce are stored before the ciphertext.
+func encryptSession(plaintext []byte, username, password string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := sessionCipher(salt, username, password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	data := append(salt, nonce...)
+	return gcm.Seal(data, nonce, plaintext, nil), nil
+}
+
+func decryptSession(data []byte, username, password string) ([]byte, error) {
+	if len(data) < saltSize {
+		return nil, fmt.Errorf("file too short")
+	}
+
+	gcm, err := sessionCipher(data[:saltSize], username, password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("file too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("saved for other credentials or corrupted")
+	}
+	return plaintext, nil
+}
+
+func sessionCipher(salt []byte, username, password string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(username+"\x00"+password), salt, 1<<15, 8, 1, keySize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
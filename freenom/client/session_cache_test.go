@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newSessionCacheClient(t *testing.T, server *testServer, path string) *Client {
+	c, err := New(Config{BaseURL: server.URL, SessionCachePath: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return c
+}
+
+func TestSessionCacheReusesSession(t *testing.T) {
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "cache", "session")
+
+	first := newSessionCacheClient(t, server, path)
+	if err := first.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the session to be saved: %s", err)
+	}
+	if bytes.Contains(data, []byte(`"session"`)) || bytes.Contains(data, []byte("login-token")) {
+		t.Errorf("expected the session cache to be encrypted")
+	}
+
+	second := newSessionCacheClient(t, server, path)
+	if err := second.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := second.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.logins != 1 {
+		t.Errorf("expected the saved session to be reused, got %d logins", server.logins)
+	}
+}
+
+func TestSessionCacheExpiredSession(t *testing.T) {
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "session")
+
+	if err := newSessionCacheClient(t, server, path).Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server.expireSession()
+
+	c := newSessionCacheClient(t, server, path)
+	if err := c.Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+		t.Fatalf("expected the client to log in again, got %s", err)
+	}
+
+	if server.logins != 2 {
+		t.Errorf("expected 2 logins, got %d", server.logins)
+	}
+
+	// the renewed session is saved for the next client
+	if err := newSessionCacheClient(t, server, path).Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.logins != 2 {
+		t.Errorf("expected the renewed session to be reused, got %d logins", server.logins)
+	}
+}
+
+func TestSessionCacheOtherCredentials(t *testing.T) {
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "session")
+
+	if err := newSessionCacheClient(t, server, path).Login(context.Background(), "user@example.com", "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err := newSessionCacheClient(t, server, path).Login(context.Background(), "user@example.com", "wrong")
+
+	if !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected the saved session to be ignored for other credentials, got %v", err)
+	}
+}
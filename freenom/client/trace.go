@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// secretParams are the form and query parameters whose values are redacted from the trace
+var secretParams = map[string]bool{"username": true, "password": true, "token": true}
+
+// secretHeaders are the headers whose values are redacted from the trace
+var secretHeaders = map[string]bool{"Cookie": true, "Set-Cookie": true, "Authorization": true, "Proxy-Authorization": true}
+
+// reHiddenSecret matches the value of the token and password inputs of the pages
+var reHiddenSecret = regexp.MustCompile(`(?is:(<input[^>]+?name="(?:token|password)"[^>]*?value=")[^"]*(")|(<input[^>]+?value=")[^"]*("[^>]*?name="(?:token|password)"))`)
+
+// traceTransport writes every request and response to a trace file, with the credentials and cookies redacted.
+// The trace is meant to be attached to bug reports when the pages of Freenom change.
+type traceTransport struct {
+	next http.RoundTripper
+	// secrets returns the credentials and session values to redact from the bodies
+	secrets func() []string
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newTraceTransport(next http.RoundTripper, filename string, secrets func() []string) (*traceTransport, error) {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return nil, fmt.Errorf("opening trace file: %w", err)
+	}
+	return &traceTransport{next: next, secrets: secrets, w: file}, nil
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	var trace strings.Builder
+	fmt.Fprintf(&trace, "=== %s %s %s\n", start.UTC().Format(time.RFC3339), req.Method, redactURL(req.URL))
+	writeHeaders(&trace, req.Header)
+	if len(requestBody) > 0 {
+		fmt.Fprintf(&trace, "\n%s\n", redactForm(string(requestBody)))
+	}
+
+	if err != nil {
+		fmt.Fprintf(&trace, "--- error after %s: %s\n\n", elapsed, err)
+		t.write(trace.String())
+		return nil, err
+	}
+
+	responseBody, readErr := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	fmt.Fprintf(&trace, "--- %s in %s\n", res.Status, elapsed)
+	writeHeaders(&trace, res.Header)
+	fmt.Fprintf(&trace, "\n%s\n\n", t.redactBody(string(responseBody)))
+
+	t.write(trace.String())
+	return res, readErr
+}
+
+func (t *traceTransport) write(trace string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	io.WriteString(t.w, trace)
+}
+
+// redactBody removes the secrets of the client and the values of the token and password inputs from the body
+func (t *traceTransport) redactBody(body string) string {
+	for _, secret := range t.secrets() {
+		if secret != "" {
+			body = strings.ReplaceAll(body, secret, redacted)
+		}
+	}
+	return reHiddenSecret.ReplaceAllString(body, "${1}${3}"+redacted+"${2}${4}")
+}
+
+func writeHeaders(w io.Writer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			if secretHeaders[key] {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	redactedURL.User = nil
+	redactedURL.RawQuery = redactForm(u.RawQuery)
+	return redactedURL.String()
+}
+
+// redactForm redacts the secret parameters of an url-encoded form or query
+func redactForm(form string) string {
+	values, err := url.ParseQuery(form)
+
+	if err != nil {
+		return redacted
+	}
+
+	for key := range values {
+		if secretParams[key] {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
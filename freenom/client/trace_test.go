@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceFile(t *testing.T) {
+	server := newTestServer(t)
+	server.records = []DomainRecord{{Type: "A", Name: "WWW", TTL: 3600, Value: "10.10.10.10"}}
+
+	traceFile := filepath.Join(t.TempDir(), "trace.log")
+
+	c, err := New(Config{BaseURL: server.URL, TraceFile: traceFile})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c.SetCredentials("user@example.com", "secret")
+
+	if _, err := c.GetDomainInfo(context.Background(), "example.tk"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(traceFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	trace := string(data)
+
+	for _, expected := range []string{"POST " + server.URL + "/dologin.php", "--- 200 OK in ", "clientarea.php?action=domains", `value="10.10.10.10"`, "Set-Cookie: REDACTED"} {
+		if !strings.Contains(trace, expected) {
+			t.Errorf("expected the trace to contain %q, got %s", expected, trace)
+		}
+	}
+
+	for _, secret := range []string{"user@example.com", "user%40example.com", "secret", "login-token", server.session} {
+		if strings.Contains(trace, secret) {
+			t.Errorf("expected %q to be redacted from the trace, got %s", secret, trace)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package freenom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// defaultProfile is the profile of the credentials file used when profile is not set
+const defaultProfile = "default"
+
+// passwordCommandTimeout bounds the run of password_command
+const passwordCommandTimeout = time.Minute
+
+// credentialsProfile is a profile of the credentials file
+type credentialsProfile struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// resolveCredentials returns the account of the provider. Every value comes from the first source setting it:
+//  1. the username and password attributes
+//  2. the password_command attribute (password only)
+//  3. the FREENOM_USERNAME and FREENOM_PASSWORD environment variables
+//  4. the FREENOM_PASSWORD_COMMAND environment variable (password only)
+//  5. the profile of the credentials file
+func resolveCredentials(ctx context.Context, config *providerData, diagnostics *diag.Diagnostics) (username, password string) {
+	username = config.Username.Value
+	if config.Username.Null {
+		username = os.Getenv("FREENOM_USERNAME")
+	}
+
+	switch {
+	case !config.Password.Null:
+		password = config.Password.Value
+	case !config.PasswordCommand.Null:
+		password = runPasswordCommand(ctx, config.PasswordCommand.Value, "password_command", diagnostics)
+	case os.Getenv("FREENOM_PASSWORD") != "":
+		password = os.Getenv("FREENOM_PASSWORD")
+	case os.Getenv("FREENOM_PASSWORD_COMMAND") != "":
+		password = runPasswordCommand(ctx, os.Getenv("FREENOM_PASSWORD_COMMAND"), "password_command", diagnostics)
+	}
+
+	credentialsFile := stringValue(config.CredentialsFile, "FREENOM_CREDENTIALS_FILE")
+	if (username != "" && password != "") || credentialsFile == "" {
+		return
+	}
+
+	profileName := stringValue(config.Profile, "FREENOM_PROFILE")
+	if profileName == "" {
+		profileName = defaultProfile
+	}
+
+	profile, err := readCredentialsFile(credentialsFile, profileName)
+
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("credentials_file"),
+			"Unable to read credentials file",
+			err.Error(),
+		)
+		return
+	}
+
+	if username == "" {
+		username = profile.Username
+	}
+	if password == "" {
+		password = profile.Password
+	}
+	return
+}
+
+// runPasswordCommand runs command with the shell and returns its output without the trailing newline
+func runPasswordCommand(ctx context.Context, command, attribute string, diagnostics *diag.Diagnostics) string {
+	ctx, cancel := context.WithTimeout(ctx, passwordCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Password command failed",
+			fmt.Sprintf("%s: %s", err, strings.TrimSpace(stderr.String())),
+		)
+		return ""
+	}
+
+	password := strings.TrimRight(stdout.String(), "\r\n")
+
+	if password == "" {
+		diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Password command failed",
+			"The command printed an empty password",
+		)
+	}
+	return password
+}
+
+// readCredentialsFile returns the profile of the credentials file.
+// The file is either a JSON object of profiles:
+//
+//	{"default": {"username": "...", "password": "..."}}
+//
+// or an INI file with a section per profile:
+//
+//	[default]
+//	username = ...
+//	password = ...
+func readCredentialsFile(filename, profileName string) (*credentialsProfile, error) {
+	data, err := os.ReadFile(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles map[string]*credentialsProfile
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json.Unmarshal(data, &profiles)
+	} else {
+		profiles, err = parseINICredentials(data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	profile, ok := profiles[profileName]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("profile %q not found in %s", profileName, filename)
+	}
+	return profile, nil
+}
+
+func parseINICredentials(data []byte) (map[string]*credentialsProfile, error) {
+	profiles := make(map[string]*credentialsProfile)
+
+	var profile *credentialsProfile
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			profile = &credentialsProfile{}
+			profiles[strings.TrimSpace(text[1:len(text)-1])] = profile
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok || profile == nil {
+			return nil, fmt.Errorf("line %d: expected a [profile] section or a key = value", line)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "username":
+			profile.Username = strings.TrimSpace(value)
+		case "password":
+			profile.Password = strings.TrimSpace(value)
+		}
+	}
+	return profiles, scanner.Err()
+}
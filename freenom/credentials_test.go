@@ -0,0 +1,153 @@
+package freenom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "credentials")
+
+	if err := os.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return filename
+}
+
+func TestReadCredentialsFile(t *testing.T) {
+	files := map[string]string{
+		"json": `{
+			"default": {"username": "default@example.com", "password": "default-secret"},
+			"work": {"username": "work@example.com", "password": "work-secret"}
+		}`,
+		"ini": `
+# freenom accounts
+[default]
+username = default@example.com
+password = default-secret
+
+[work]
+username = work@example.com
+password = work-secret
+`,
+	}
+
+	for format, content := range files {
+		t.Run(format, func(t *testing.T) {
+			filename := writeCredentialsFile(t, content)
+
+			profile, err := readCredentialsFile(filename, "work")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if profile.Username != "work@example.com" || profile.Password != "work-secret" {
+				t.Errorf("unexpected profile %+v", profile)
+			}
+
+			if _, err := readCredentialsFile(filename, "missing"); err == nil {
+				t.Errorf("expected an error for a missing profile")
+			}
+		})
+	}
+}
+
+func TestResolveCredentialsPrecedence(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the password commands use a unix shell")
+	}
+
+	t.Setenv("FREENOM_USERNAME", "")
+	t.Setenv("FREENOM_PASSWORD", "")
+	t.Setenv("FREENOM_PASSWORD_COMMAND", "")
+
+	filename := writeCredentialsFile(t, `{"default": {"username": "file@example.com", "password": "file-secret"}}`)
+
+	tests := map[string]struct {
+		modify           func(config *providerData)
+		env              map[string]string
+		expectedUsername string
+		expectedPassword string
+	}{
+		"credentials file": {
+			expectedUsername: "file@example.com",
+			expectedPassword: "file-secret",
+		},
+		"environment": {
+			env:              map[string]string{"FREENOM_USERNAME": "env@example.com", "FREENOM_PASSWORD": "env-secret"},
+			expectedUsername: "env@example.com",
+			expectedPassword: "env-secret",
+		},
+		"password command environment": {
+			env:              map[string]string{"FREENOM_PASSWORD_COMMAND": "echo env-command-secret"},
+			expectedUsername: "file@example.com",
+			expectedPassword: "env-command-secret",
+		},
+		"password command": {
+			modify: func(config *providerData) {
+				config.PasswordCommand = types.String{Value: "echo command-secret"}
+			},
+			env:              map[string]string{"FREENOM_PASSWORD": "env-secret"},
+			expectedUsername: "file@example.com",
+			expectedPassword: "command-secret",
+		},
+		"attributes": {
+			modify: func(config *providerData) {
+				config.Username = types.String{Value: "attribute@example.com"}
+				config.Password = types.String{Value: "attribute-secret"}
+			},
+			env:              map[string]string{"FREENOM_USERNAME": "env@example.com", "FREENOM_PASSWORD": "env-secret"},
+			expectedUsername: "attribute@example.com",
+			expectedPassword: "attribute-secret",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			config := testProviderData()
+			config.Username = types.String{Null: true}
+			config.Password = types.String{Null: true}
+			config.CredentialsFile = types.String{Value: filename}
+			if test.modify != nil {
+				test.modify(config)
+			}
+
+			var diags diag.Diagnostics
+			username, password := resolveCredentials(context.Background(), config, &diags)
+			checkNoErrors(t, diags)
+
+			if username != test.expectedUsername || password != test.expectedPassword {
+				t.Errorf("expected %s/%s, got %s/%s", test.expectedUsername, test.expectedPassword, username, password)
+			}
+		})
+	}
+}
+
+func TestResolveCredentialsPasswordCommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the password commands use a unix shell")
+	}
+
+	config := testProviderData()
+	config.Password = types.String{Null: true}
+	config.PasswordCommand = types.String{Value: "echo locked >&2; exit 1"}
+
+	var diags diag.Diagnostics
+	resolveCredentials(context.Background(), config, &diags)
+
+	if !diags.HasError() {
+		t.Fatalf("expected an error when the password command fails")
+	}
+}
@@ -3,18 +3,17 @@ package freenom
 import (
 	"context"
 	"fmt"
-	"log"
+	"terraform-provider-frenom/freenom/client"
 	"terraform-provider-frenom/freenom/validators"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	gofreenom "github.com/tzwsoho/go-freenom/freenom"
 )
 
 var _ datasource.DataSource = &dnsRecordDataSource{}
+var _ datasource.DataSourceWithConfigure = &dnsRecordDataSource{}
 
 type dnsRecordDataSource struct {
 	provider *freenomProvider
@@ -28,7 +27,7 @@ func (d *dnsRecordDataSource) Metadata(ctx context.Context, req datasource.Metad
 	resp.TypeName = req.ProviderTypeName + "_dns_record"
 }
 
-func (r *dnsRecordDataSource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+func (r *dnsRecordDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
@@ -38,14 +37,14 @@ func (r *dnsRecordDataSource) Configure(ctx context.Context, req resource.Config
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
+			"Unexpected Data Source Configure Type",
 			fmt.Sprintf("Expected *freenomProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	if !provider.configured {
+	if !provider.configured && !provider.deferred {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
 			"Expected a configured provider but it wasn't. Please report this issue to the provider developers.",
@@ -63,7 +62,7 @@ func (r *dnsRecordDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag
 			"id": {
 				Type:        types.StringType,
 				Computed:    true,
-				Description: "Unique identifier for this resource (<name>/<domain>)",
+				Description: "Unique identifier for this resource (<domain>/<name>/<type>/<value hash>)",
 			},
 			"domain": {
 				Type: types.StringType,
@@ -76,21 +75,21 @@ func (r *dnsRecordDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag
 			},
 			"type": {
 				Type:        types.StringType,
+				Optional:    true,
 				Computed:    true,
-				Required:    false,
-				Description: "The DNS type of the record",
+				Description: "The DNS type of the record, required when several records have the name",
 			},
 			"name": {
 				Type: types.StringType,
 				// Computed: false,
 				Required:    true,
-				Description: "The name of the record (Subdomain)",
+				Description: "The name of the record (Subdomain), empty or @ for the domain itself",
 			},
 			"value": {
 				Type:        types.StringType,
+				Optional:    true,
 				Computed:    true,
-				Required:    false,
-				Description: "The value of the record (Ex. Ip Address)",
+				Description: "The value of the record (Ex. Ip Address), required when several records have the name and type",
 			},
 			"priority": {
 				Type:        types.Int64Type,
@@ -115,6 +114,11 @@ func (r *dnsRecordDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag
 }
 
 func (d *dnsRecordDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.provider.deferred {
+		addDeferredError(&resp.Diagnostics)
+		return
+	}
+
 	if !d.provider.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -124,7 +128,7 @@ func (d *dnsRecordDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	var datasourceRecord FreenomDnsRecord
-	var freenomRecord *gofreenom.DomainRecord
+	var freenomRecord *client.DomainRecord
 
 	diags := req.Config.Get(ctx, &datasourceRecord)
 	resp.Diagnostics.Append(diags...)
@@ -132,17 +136,23 @@ func (d *dnsRecordDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
-	log.Println("[INFO] Reading record", datasourceRecord.Domain, datasourceRecord.Name)
+	ctx = logContext(ctx, "read", datasourceRecord.Domain.Value, map[string]interface{}{"name": datasourceRecord.Name.Value, "type": datasourceRecord.Type.Value})
+	logDebug(ctx, "Reading record", nil)
 
-	freenomRecord, err := getRecordByName(datasourceRecord.Domain.Value, datasourceRecord.Name.Value, &resp.Diagnostics)
+	freenomRecord, err := getRecordByName(ctx, d.provider.client, datasourceRecord.Domain.Value, datasourceRecord.Name.Value, datasourceRecord.Type.Value, datasourceRecord.Value.Value, &resp.Diagnostics)
 
 	if err != nil {
 		return
 	}
 
-	datasourceRecord.ID = types.String{Value: computeID(datasourceRecord.Domain.Value, datasourceRecord.Name.Value)}
-	datasourceRecord.Value = types.String{Value: freenomRecord.Value}
-	datasourceRecord.Type = types.String{Value: freenomRecord.Type}
+	datasourceRecord.ID = types.String{Value: computeID(datasourceRecord.Domain.Value, freenomRecord.Name, freenomRecord.Type, freenomRecord.Value)}
+	// the configured filters are kept as written
+	if datasourceRecord.Value.Null {
+		datasourceRecord.Value = types.String{Value: normalizeValue(freenomRecord.Type, freenomRecord.Value)}
+	}
+	if datasourceRecord.Type.Null {
+		datasourceRecord.Type = types.String{Value: freenomRecord.Type}
+	}
 	datasourceRecord.TTL = types.Int64{Value: int64(freenomRecord.TTL)}
 	datasourceRecord.Priority = types.Int64{Value: int64(freenomRecord.Priority)}
 	datasourceRecord.FQDN = types.String{Value: computeFQDN(datasourceRecord.Domain.Value, datasourceRecord.Name.Value)}
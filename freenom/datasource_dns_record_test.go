@@ -0,0 +1,205 @@
+package freenom
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDnsRecordDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "WWW", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "MX", Name: "MAIL", Value: "mx.example.tk", TTL: 300, Priority: 10},
+	)
+
+	d := &dnsRecordDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	req := datasource.ReadRequest{
+		Config: testConfig(t, schema, &FreenomDnsRecord{
+			ID:       types.String{Null: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Null: true},
+			Name:     types.String{Value: "mail"},
+			Value:    types.String{Null: true},
+			Priority: types.Int64{Null: true},
+			TTL:      types.Int64{Null: true},
+			FQDN:     types.String{Null: true},
+		}),
+	}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state FreenomDnsRecord
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.ID.Value != "example.tk/mail/MX/"+hashValue("MX", "mx.example.tk") || state.FQDN.Value != "mail.example.tk" {
+		t.Errorf("unexpected id %q or fqdn %q", state.ID.Value, state.FQDN.Value)
+	}
+	if state.Type.Value != "MX" || state.Value.Value != "mx.example.tk" || state.TTL.Value != 300 || state.Priority.Value != 10 {
+		t.Errorf("unexpected record %+v", state)
+	}
+}
+
+func TestDnsRecordDataSourceReadApex(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "", Value: "10.10.10.10", TTL: 3600},
+	)
+
+	d := &dnsRecordDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	req := datasource.ReadRequest{
+		Config: testConfig(t, schema, &FreenomDnsRecord{
+			ID:       types.String{Null: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Null: true},
+			Name:     types.String{Value: "@"},
+			Value:    types.String{Null: true},
+			Priority: types.Int64{Null: true},
+			TTL:      types.Int64{Null: true},
+			FQDN:     types.String{Null: true},
+		}),
+	}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state FreenomDnsRecord
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.Value.Value != "10.10.10.10" || state.FQDN.Value != "example.tk" || state.ID.Value != "example.tk/@/A/"+hashValue("A", "10.10.10.10") {
+		t.Errorf("unexpected apex record %+v", state)
+	}
+}
+
+func TestDnsRecordDataSourceReadNotFound(t *testing.T) {
+	ctx := context.Background()
+	d := &dnsRecordDataSource{provider: newTestProvider(newTestFake())}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	req := datasource.ReadRequest{
+		Config: testConfig(t, schema, &FreenomDnsRecord{
+			ID:       types.String{Null: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Null: true},
+			Name:     types.String{Value: "www"},
+			Value:    types.String{Null: true},
+			Priority: types.Int64{Null: true},
+			TTL:      types.Int64{Null: true},
+			FQDN:     types.String{Null: true},
+		}),
+	}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error reading a missing record")
+	}
+}
+
+func TestDnsRecordDataSourceReadDeferred(t *testing.T) {
+	ctx := context.Background()
+	d := &dnsRecordDataSource{provider: &freenomProvider{deferred: true}}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	req := datasource.ReadRequest{
+		Config: testConfig(t, schema, &FreenomDnsRecord{
+			ID:       types.String{Null: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Null: true},
+			Name:     types.String{Value: "www"},
+			Value:    types.String{Null: true},
+			Priority: types.Int64{Null: true},
+			TTL:      types.Int64{Null: true},
+			FQDN:     types.String{Null: true},
+		}),
+	}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error reading while the provider configuration is not known")
+	}
+}
+
+func TestDnsRecordDataSourceReadFilters(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 300},
+	)
+
+	d := &dnsRecordDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	read := func(recordType, value types.String) datasource.ReadResponse {
+		req := datasource.ReadRequest{
+			Config: testConfig(t, schema, &FreenomDnsRecord{
+				ID:       types.String{Null: true},
+				Domain:   types.String{Value: "example.tk"},
+				Type:     recordType,
+				Name:     types.String{Value: "www"},
+				Value:    value,
+				Priority: types.Int64{Null: true},
+				TTL:      types.Int64{Null: true},
+				FQDN:     types.String{Null: true},
+			}),
+		}
+		resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+		d.Read(ctx, req, &resp)
+		return resp
+	}
+
+	resp := read(types.String{Null: true}, types.String{Null: true})
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error reading a name with several records")
+	}
+	detail := resp.Diagnostics.Errors()[0].Detail()
+	for _, id := range []string{"example.tk/www/A/" + hashValue("A", "10.10.10.10"), "example.tk/www/AAAA/" + hashValue("AAAA", "2001:db8::1")} {
+		if !strings.Contains(detail, id) {
+			t.Errorf("expected the error to list the candidate %s, got %q", id, detail)
+		}
+	}
+
+	for name, filters := range map[string][2]types.String{
+		"type":  {types.String{Value: "aaaa"}, types.String{Null: true}},
+		"value": {types.String{Null: true}, types.String{Value: "2001:DB8:0::1"}},
+	} {
+		resp := read(filters[0], filters[1])
+		checkNoErrors(t, resp.Diagnostics)
+
+		var state FreenomDnsRecord
+		checkNoErrors(t, resp.State.Get(ctx, &state))
+
+		if state.ID.Value != "example.tk/www/AAAA/"+hashValue("AAAA", "2001:db8::1") || state.TTL.Value != 300 {
+			t.Errorf("%s: expected the AAAA record, got %+v", name, state)
+		}
+		if state.Type != filters[0] && !filters[0].Null || state.Value != filters[1] && !filters[1].Null {
+			t.Errorf("%s: expected the configured filters to be kept, got %+v", name, state)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package freenom
+
+import (
+	"context"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestDnsRecordListDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "api", Value: "10.10.10.11", TTL: 300},
+		client.DomainRecord{Type: "A", Name: "", Value: "10.10.10.12", TTL: 3600},
+	)
+
+	d := &dnsRecordListDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	var config struct {
+		Domain  string             `tfsdk:"domain"`
+		Records []FreenomDnsRecord `tfsdk:"records"`
+	}
+	config.Domain = "example.tk"
+
+	req := datasource.ReadRequest{Config: testConfig(t, schema, &config)}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state struct {
+		Domain  string             `tfsdk:"domain"`
+		Records []FreenomDnsRecord `tfsdk:"records"`
+	}
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if len(state.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(state.Records))
+	}
+
+	api := state.Records[1]
+	if api.ID.Value != computeID("example.tk", "api", "A", "10.10.10.11") || api.Domain.Value != "example.tk" || api.Value.Value != "10.10.10.11" || api.TTL.Value != 300 {
+		t.Errorf("unexpected record %+v", api)
+	}
+
+	// the apex is named "@" as in the resource and the import
+	apex := state.Records[2]
+	if apex.Name.Value != "@" || apex.FQDN.Value != "example.tk" || apex.ID.Value != computeID("example.tk", "@", "A", "10.10.10.12") {
+		t.Errorf("unexpected apex record %+v", apex)
+	}
+}
+
+func TestDnsRecordListDataSourceReadUnknownDomain(t *testing.T) {
+	ctx := context.Background()
+	d := &dnsRecordListDataSource{provider: newTestProvider(newTestFake())}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	var config struct {
+		Domain  string             `tfsdk:"domain"`
+		Records []FreenomDnsRecord `tfsdk:"records"`
+	}
+	config.Domain = "other.tk"
+
+	req := datasource.ReadRequest{Config: testConfig(t, schema, &config)}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error reading a domain outside the account")
+	}
+}
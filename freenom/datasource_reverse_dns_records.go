@@ -3,17 +3,16 @@ package freenom
 import (
 	"context"
 	"fmt"
-	"log"
 	"terraform-provider-frenom/freenom/validators"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var _ datasource.DataSource = &reverseDnsRecordListDataSource{}
+var _ datasource.DataSourceWithConfigure = &reverseDnsRecordListDataSource{}
 
 type reverseDnsRecordListDataSource struct {
 	provider *freenomProvider
@@ -27,7 +26,7 @@ func (d *reverseDnsRecordListDataSource) Metadata(ctx context.Context, req datas
 	resp.TypeName = req.ProviderTypeName + "_reverse_dns_records" // TODO rename to _reverse_dns_record_list
 }
 
-func (r *reverseDnsRecordListDataSource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+func (r *reverseDnsRecordListDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
@@ -37,14 +36,14 @@ func (r *reverseDnsRecordListDataSource) Configure(ctx context.Context, req reso
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
+			"Unexpected Data Source Configure Type",
 			fmt.Sprintf("Expected *freenomProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	if !provider.configured {
+	if !provider.configured && !provider.deferred {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
 			"Expected a configured provider but it wasn't. Please report this issue to the provider developers.",
@@ -82,7 +81,7 @@ func (r *reverseDnsRecordListDataSource) GetSchema(ctx context.Context) (tfsdk.S
 						Type:        types.StringType,
 						Computed:    true,
 						Required:    false,
-						Description: "Unique identifier for this resource (<name>/<domain>)",
+						Description: "Unique identifier for this resource (<domain>/<name>/<type>/<value hash>)",
 					},
 					"domain": {
 						Type:        types.StringType,
@@ -100,7 +99,7 @@ func (r *reverseDnsRecordListDataSource) GetSchema(ctx context.Context) (tfsdk.S
 						Type:        types.StringType,
 						Computed:    true,
 						Required:    false,
-						Description: "The name of the record (Subdomain)",
+						Description: "The name of the record (Subdomain), @ for the domain itself",
 					},
 					"value": {
 						Type:        types.StringType,
@@ -133,6 +132,11 @@ func (r *reverseDnsRecordListDataSource) GetSchema(ctx context.Context) (tfsdk.S
 }
 
 func (d *reverseDnsRecordListDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.provider.deferred {
+		addDeferredError(&resp.Diagnostics)
+		return
+	}
+
 	if !d.provider.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -153,21 +157,23 @@ func (d *reverseDnsRecordListDataSource) Read(ctx context.Context, req datasourc
 		return
 	}
 
-	freenomRecords, err := getAllRecordsByDomainNameAndValue(resourceState.Domain, resourceState.Value, &resp.Diagnostics)
+	ctx = logContext(ctx, "read", resourceState.Domain, map[string]interface{}{"value": resourceState.Value})
+
+	freenomRecords, err := getAllRecordsByDomainNameAndValue(ctx, d.provider.client, resourceState.Domain, resourceState.Value, &resp.Diagnostics)
 
 	if err != nil {
 		return
 	}
 
-	log.Printf("[INFO] Found %d records", len(freenomRecords))
+	logDebug(ctx, "Found records", map[string]interface{}{"records": len(freenomRecords)})
 
 	for _, freenomRecord := range freenomRecords {
 		var datasourceRecord FreenomDnsRecord
-		datasourceRecord.ID = types.String{Value: computeID(resourceState.Domain, freenomRecord.Name)}
+		datasourceRecord.ID = types.String{Value: computeID(resourceState.Domain, freenomRecord.Name, freenomRecord.Type, freenomRecord.Value)}
 		datasourceRecord.Domain = types.String{Value: resourceState.Domain}
 		datasourceRecord.Type = types.String{Value: freenomRecord.Type}
-		datasourceRecord.Name = types.String{Value: freenomRecord.Name}
-		datasourceRecord.Value = types.String{Value: freenomRecord.Value}
+		datasourceRecord.Name = types.String{Value: importName(freenomRecord.Name)}
+		datasourceRecord.Value = types.String{Value: normalizeValue(freenomRecord.Type, freenomRecord.Value)}
 		datasourceRecord.Priority = types.Int64{Value: int64(freenomRecord.Priority)}
 		datasourceRecord.TTL = types.Int64{Value: int64(freenomRecord.TTL)}
 		datasourceRecord.FQDN = types.String{Value: computeFQDN(resourceState.Domain, freenomRecord.Name)}
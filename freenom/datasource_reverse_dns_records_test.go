@@ -0,0 +1,109 @@
+package freenom
+
+import (
+	"context"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestReverseDnsRecordListDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "api", Value: "10.10.10.11", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "grafana", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "", Value: "10.10.10.10", TTL: 3600},
+	)
+
+	d := &reverseDnsRecordListDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	var config struct {
+		Domain  string             `tfsdk:"domain"`
+		Value   string             `tfsdk:"value"`
+		Records []FreenomDnsRecord `tfsdk:"records"`
+	}
+	config.Domain = "example.tk"
+	config.Value = "10.10.10.10"
+
+	req := datasource.ReadRequest{Config: testConfig(t, schema, &config)}
+	resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+	d.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state struct {
+		Domain  string             `tfsdk:"domain"`
+		Value   string             `tfsdk:"value"`
+		Records []FreenomDnsRecord `tfsdk:"records"`
+	}
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if len(state.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(state.Records))
+	}
+
+	for _, record := range state.Records {
+		if record.Value.Value != "10.10.10.10" {
+			t.Errorf("unexpected record %+v", record)
+		}
+	}
+
+	if state.Records[0].Name.Value != "www" || state.Records[1].Name.Value != "grafana" {
+		t.Errorf("unexpected records order %q, %q", state.Records[0].Name.Value, state.Records[1].Name.Value)
+	}
+
+	// the apex is named "@" as in the resource and the import
+	if state.Records[2].Name.Value != "@" || state.Records[2].FQDN.Value != "example.tk" {
+		t.Errorf("unexpected apex record %+v", state.Records[2])
+	}
+}
+
+func TestReverseDnsRecordListDataSourceReadEquivalentValue(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:DB8:0:0:0:0:0:1", TTL: 3600},
+		client.DomainRecord{Type: "CNAME", Name: "api", Value: "WWW.example.tk", TTL: 3600},
+	)
+
+	d := &reverseDnsRecordListDataSource{provider: newTestProvider(fake)}
+
+	schema, diags := d.GetSchema(ctx)
+	checkNoErrors(t, diags)
+
+	tests := map[string]string{
+		"2001:db8::1":     "2001:db8::1",
+		"www.example.tk.": "www.example.tk",
+	}
+
+	for value, expected := range tests {
+		var config struct {
+			Domain  string             `tfsdk:"domain"`
+			Value   string             `tfsdk:"value"`
+			Records []FreenomDnsRecord `tfsdk:"records"`
+		}
+		config.Domain = "example.tk"
+		config.Value = value
+
+		req := datasource.ReadRequest{Config: testConfig(t, schema, &config)}
+		resp := datasource.ReadResponse{State: testState(t, schema, nil)}
+
+		d.Read(ctx, req, &resp)
+		checkNoErrors(t, resp.Diagnostics)
+
+		var state struct {
+			Domain  string             `tfsdk:"domain"`
+			Value   string             `tfsdk:"value"`
+			Records []FreenomDnsRecord `tfsdk:"records"`
+		}
+		checkNoErrors(t, resp.State.Get(ctx, &state))
+
+		if len(state.Records) != 1 || state.Records[0].Value.Value != expected {
+			t.Errorf("expected the record with the value %q for %q, got %+v", expected, value, state.Records)
+		}
+	}
+}
@@ -14,3 +14,52 @@ type FreenomDnsRecord struct {
 	TTL      types.Int64  `tfsdk:"ttl"`
 	FQDN     types.String `tfsdk:"fqdn"`
 }
+
+// FreenomDnsRecordResource is the freenom_dns_record resource, a FreenomDnsRecord with the timeouts of its operations
+type FreenomDnsRecordResource struct {
+	ID       types.String `tfsdk:"id"`
+	Domain   types.String `tfsdk:"domain"`
+	Type     types.String `tfsdk:"type"`
+	Name     types.String `tfsdk:"name"`
+	Value    types.String `tfsdk:"value"`
+	Priority types.Int64  `tfsdk:"priority"`
+	TTL      types.Int64  `tfsdk:"ttl"`
+	FQDN     types.String `tfsdk:"fqdn"`
+	Timeouts *Timeouts    `tfsdk:"timeouts"`
+}
+
+// Timeouts of the operations of a resource, as durations (Ex. 5m)
+type Timeouts struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+func (t *Timeouts) create() types.String {
+	if t == nil {
+		return types.String{Null: true}
+	}
+	return t.Create
+}
+
+func (t *Timeouts) read() types.String {
+	if t == nil {
+		return types.String{Null: true}
+	}
+	return t.Read
+}
+
+func (t *Timeouts) update() types.String {
+	if t == nil {
+		return types.String{Null: true}
+	}
+	return t.Update
+}
+
+func (t *Timeouts) delete() types.String {
+	if t == nil {
+		return types.String{Null: true}
+	}
+	return t.Delete
+}
@@ -0,0 +1,35 @@
+package freenom
+
+import (
+	"net"
+	"strings"
+)
+
+// normalizeValue returns the canonical form of the value of a record, as Freenom may rewrite it:
+// compressed IPv6 addresses, lowercase hostnames without the trailing dot and TXT values without quotes
+func normalizeValue(recordType, value string) string {
+	value = strings.TrimSpace(value)
+
+	switch strings.ToUpper(recordType) {
+	case "AAAA":
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+	case "CNAME", "MX":
+		return strings.TrimSuffix(strings.ToLower(value), ".")
+	case "TXT":
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && !strings.Contains(value[1:len(value)-1], `"`) {
+			return value[1 : len(value)-1]
+		}
+	case "LOC", "NAPTR":
+		return strings.Join(strings.Fields(value), " ")
+	case "RP":
+		return strings.ToLower(strings.Join(strings.Fields(value), " "))
+	}
+	return value
+}
+
+// sameValue reports whether the values are the same value of a record of the type
+func sameValue(recordType, a, b string) bool {
+	return a == b || normalizeValue(recordType, a) == normalizeValue(recordType, b)
+}
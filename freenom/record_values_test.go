@@ -0,0 +1,31 @@
+package freenom
+
+import "testing"
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		recordType string
+		value      string
+		expected   string
+	}{
+		{"A", " 10.10.10.10 ", "10.10.10.10"},
+		{"AAAA", "2001:DB8:0000:0:0:0:0:1", "2001:db8::1"},
+		{"CNAME", "WWW.Example.tk.", "www.example.tk"},
+		{"MX", "mail.example.tk.", "mail.example.tk"},
+		{"TXT", `"v=spf1 -all"`, "v=spf1 -all"},
+		{"TXT", `"a" "b"`, `"a" "b"`},
+		{"TXT", "Case Sensitive", "Case Sensitive"},
+		{"LOC", "52  22 23.000 N 4 53 32.000 E  -2.00m", "52 22 23.000 N 4 53 32.000 E -2.00m"},
+		{"RP", "Admin.example.tk.  info.example.tk.", "admin.example.tk. info.example.tk."},
+	}
+
+	for _, test := range tests {
+		if normalized := normalizeValue(test.recordType, test.value); normalized != test.expected {
+			t.Errorf("expected %q for the %s value %q, got %q", test.expected, test.recordType, test.value, normalized)
+		}
+	}
+
+	if sameValue("TXT", "abc", "ABC") {
+		t.Errorf("expected the TXT values to be case sensitive")
+	}
+}
@@ -2,9 +2,10 @@ package freenom
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"terraform-provider-frenom/freenom/client"
 	"terraform-provider-frenom/freenom/validators"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
@@ -14,12 +15,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/tzwsoho/go-freenom/freenom"
 )
 
 // var _ provider.ResourceType = freenomDnsRecordResourceType{}
 var _ resource.Resource = &dnsRecordResource{}
 var _ resource.ResourceWithImportState = &dnsRecordResource{}
+var _ resource.ResourceWithModifyPlan = &dnsRecordResource{}
+var _ resource.ResourceWithValidateConfig = &dnsRecordResource{}
+var _ resource.ResourceWithConfigValidators = &dnsRecordResource{}
 
 type dnsRecordResource struct {
 	provider *freenomProvider
@@ -50,7 +53,7 @@ func (r *dnsRecordResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	if !provider.configured {
+	if !provider.configured && !provider.deferred {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
 			"Expected a configured provider but it wasn't. Please report this issue to the provider developers.",
@@ -69,7 +72,7 @@ func (r *dnsRecordResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.D
 			"id": {
 				Type:        types.StringType,
 				Computed:    true,
-				Description: "Unique identifier for this resource (<name>/<domain>)",
+				Description: "Unique identifier for this resource (<domain>/<name>/<type>/<value hash>)",
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.UseStateForUnknown(),
 				},
@@ -103,48 +106,146 @@ func (r *dnsRecordResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.D
 			"name": {
 				Type:        types.StringType,
 				Required:    true,
-				Description: "The name of the record (Subdomain)",
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					resource.RequiresReplace(),
-				},
+				Description: "The name of the record (Subdomain), empty or @ for the domain itself",
 			},
 			"value": {
 				Type:        types.StringType,
 				Required:    true,
-				Description: "The value of the record (Ex. Ip Address)",
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					resource.RequiresReplace(),
-				},
+				Description: "The value of the record (Ex. Ip Address), validated by the type of the record",
 			},
 			"priority": {
 				Type:        types.Int64Type,
-				Required:    true,
-				Description: "The priority of the record",
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					resource.RequiresReplace(),
-				},
+				Optional:    true,
+				Computed:    true,
+				Description: "The priority of the record, required for the MX records and only allowed for them. Defaults to 0",
 			},
 			"ttl": {
 				Type:        types.Int64Type,
-				Required:    true,
-				Description: "The TTL of the record",
+				Optional:    true,
+				Computed:    true,
+				Description: "The TTL of the record. Defaults to the default_ttl of the provider",
 				Validators: []tfsdk.AttributeValidator{
 					int64validator.AtLeast(1),
 					// validators.IntGreaterThan(1),
 				},
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					resource.RequiresReplace(),
-				},
 			},
 			"fqdn": {
 				Type:        types.StringType,
 				Computed:    true,
 				Description: "The fully qualified domain name of the record (<name>.<domain>)",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": {
+				NestingMode: tfsdk.BlockNestingModeSingle,
+				Description: "How long the operations wait for Freenom before failing, as durations (Ex. 30s, 5m). Defaults to 5m.",
+				Attributes: map[string]tfsdk.Attribute{
+					"create": timeoutAttribute("create"),
+					"read":   timeoutAttribute("read"),
+					"update": timeoutAttribute("update"),
+					"delete": timeoutAttribute("delete"),
+				},
 			},
 		},
 	}, nil
 }
 
+func (r *dnsRecordResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		validators.RecordValue(path.Root("type"), path.Root("value")),
+	}
+}
+
+// ValidateConfig requires a priority for the MX records and rejects it for the other types
+func (r *dnsRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var recordType types.String
+	var priority types.Int64
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &recordType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("priority"), &priority)...)
+	if resp.Diagnostics.HasError() || recordType.Null || recordType.Unknown {
+		return
+	}
+
+	if recordType.Value == "MX" && priority.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("priority"),
+			"Missing priority",
+			"The MX records require a priority.",
+		)
+	}
+
+	// an unknown priority may still turn out null, it is checked once known
+	if recordType.Value != "MX" && !priority.Null && !priority.Unknown {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("priority"),
+			"Unexpected priority",
+			fmt.Sprintf("Only the MX records have a priority, remove it from the %s record.", recordType.Value),
+		)
+	}
+}
+
+// ModifyPlan plans the default ttl and priority when they are not set,
+// a new id when the name or the value of the record changes, and a new fqdn when its name changes
+func (r *dnsRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan FreenomDnsRecordResource
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the default ttl is not known until the provider configuration is
+	if config.TTL.Null && r.provider != nil && r.provider.defaultTTL > 0 {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ttl"), types.Int64{Value: r.provider.defaultTTL})...)
+	}
+	if config.Priority.Null {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("priority"), types.Int64{Value: 0})...)
+	}
+
+	// the id and the fqdn of a new record are known once it is created
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state FreenomDnsRecordResource
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// an equivalent value, such as a hostname with a trailing dot, keeps the id
+	valueChanged := plan.Value.Unknown || !sameValue(plan.Type.Value, plan.Value.Value, state.Value.Value)
+
+	if !plan.Name.Equal(state.Name) || valueChanged {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.String{Unknown: true})...)
+	}
+	if !plan.Name.Equal(state.Name) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fqdn"), types.String{Unknown: true})...)
+	}
+}
+
+func timeoutAttribute(operation string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Type:        types.StringType,
+		Optional:    true,
+		Description: "Timeout of the " + operation + " of the record",
+		Validators: []tfsdk.AttributeValidator{
+			validators.IsDuration(),
+		},
+	}
+}
+
 // Create a new resource
 func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	if !r.provider.configured {
@@ -155,19 +256,23 @@ func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	var plan FreenomDnsRecord
+	var plan FreenomDnsRecordResource
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// log.Println("[INFO] Creating record", plan.Name.Value, plan.Value.Value)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.create())
+	defer cancel()
 
-	err := freenom.AddRecord(plan.Domain.Value, []freenom.DomainRecord{
+	ctx = logContext(ctx, "create", plan.Domain.Value, map[string]interface{}{"name": plan.Name.Value, "type": plan.Type.Value})
+	logDebug(ctx, "Creating record", nil)
+
+	err := r.provider.client.AddRecord(ctx, plan.Domain.Value, []client.DomainRecord{
 		{
 			Type:     plan.Type.Value,
-			Name:     strings.ToLower(plan.Name.Value),
+			Name:     normalizeName(plan.Name.Value),
 			Value:    plan.Value.Value,
 			Priority: int(plan.Priority.Value),
 			TTL:      int(plan.TTL.Value),
@@ -175,14 +280,11 @@ func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateReque
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating record",
-			err.Error(),
-		)
+		addClientError(&resp.Diagnostics, "Error creating record", err)
 		return
 	}
 
-	plan.ID = types.String{Value: computeID(plan.Domain.Value, plan.Name.Value)}
+	plan.ID = types.String{Value: computeID(plan.Domain.Value, plan.Name.Value, plan.Type.Value, plan.Value.Value)}
 	plan.FQDN = types.String{Value: computeFQDN(plan.Domain.Value, plan.Name.Value)}
 
 	diags = resp.State.Set(ctx, plan)
@@ -194,6 +296,11 @@ func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateReque
 
 // Read resource information
 func (r *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.provider.deferred {
+		// the provider configuration is known only at apply, keep the prior state until then
+		return
+	}
+
 	if !r.provider.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -202,38 +309,57 @@ func (r *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	var state FreenomDnsRecord
+	var state FreenomDnsRecordResource
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	domain, name, err := parseID(state.ID.Value)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.read())
+	defer cancel()
+
+	id, err := stateRecordID(&state)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error parsing id"+state.ID.Value,
+			"Error parsing id "+state.ID.Value,
 			err.Error(),
 		)
 		return
 	}
 
-	log.Println("[INFO] Reading record ", state.ID.Value, domain, name)
+	ctx = logContext(ctx, "read", id.Domain, map[string]interface{}{"name": id.Name, "type": id.Type})
+	logDebug(ctx, "Reading record", nil)
 
-	record, err := getRecordByName(domain, name, &resp.Diagnostics)
+	record, err := findRecord(ctx, r.provider.client, id)
 
+	if errors.Is(err, client.ErrRecordNotFound) {
+		// the record was deleted outside of terraform, so it is planned to be created again
+		logDebug(ctx, "Record not found, removing it from the state", nil)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
+		addClientError(&resp.Diagnostics, "Error reading record "+state.ID.Value, err)
 		return
 	}
 
-	state.ID = types.String{Value: computeID(domain, name)}
+	// the legacy ids are upgraded to the ids with the type and the value hash
+	state.ID = types.String{Value: computeID(id.Domain, record.Name, record.Type, record.Value)}
+	state.Domain = types.String{Value: id.Domain}
 	state.Type = types.String{Value: record.Type}
-	state.Name = types.String{Value: strings.ToLower(record.Name)}
-	state.Value = types.String{Value: record.Value}
+	// keep the name as configured, such as "@" for the apex, unless it changed
+	if !sameName(state.Name.Value, record.Name) {
+		state.Name = types.String{Value: strings.ToLower(record.Name)}
+	}
+	// keep the value as configured unless Freenom has a different value, not just another form of it
+	if !sameValue(record.Type, state.Value.Value, record.Value) {
+		state.Value = types.String{Value: record.Value}
+	}
 	state.Priority = types.Int64{Value: int64(record.Priority)}
 	state.TTL = types.Int64{Value: int64(record.TTL)}
-	state.FQDN = types.String{Value: computeFQDN(domain, name)}
+	state.FQDN = types.String{Value: computeFQDN(id.Domain, record.Name)}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -252,20 +378,23 @@ func (r *dnsRecordResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	var state FreenomDnsRecord
+	var state FreenomDnsRecordResource
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var plan FreenomDnsRecord
+	var plan FreenomDnsRecordResource
 	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.update())
+	defer cancel()
+
 	if state.Domain.Value != plan.Domain.Value {
 		resp.Diagnostics.AddError(
 			"Domain changed",
@@ -274,36 +403,56 @@ func (r *dnsRecordResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	domain := plan.Domain.Value
+	id, err := stateRecordID(&state)
 
-	oldRecord := &freenom.DomainRecord{
-		Type:     state.Type.Value,
-		Name:     strings.ToLower(state.Name.Value),
-		Value:    state.Value.Value,
-		Priority: int(state.Priority.Value),
-		TTL:      int(state.TTL.Value),
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing id "+state.ID.Value,
+			err.Error(),
+		)
+		return
 	}
 
-	newRecord := &freenom.DomainRecord{
+	domain := plan.Domain.Value
+
+	newRecord := &client.DomainRecord{
 		Type:     plan.Type.Value,
-		Name:     strings.ToLower(plan.Name.Value),
+		Name:     normalizeName(plan.Name.Value),
 		Value:    plan.Value.Value,
 		Priority: int(plan.Priority.Value),
 		TTL:      int(plan.TTL.Value),
 	}
 
-	log.Printf("[DEBUG] Old record: %v\n", *oldRecord)
-	log.Printf("[DEBUG] New record: %v\n", *newRecord)
-
-	log.Printf("[DEBUG] Domain: %v\n", domain)
+	ctx = logContext(ctx, "update", domain, map[string]interface{}{"name": newRecord.Name, "type": newRecord.Type})
 
-	err := freenom.ModifyRecord(domain, oldRecord, newRecord)
+	// the record is modified as Freenom has it, which may have drifted from the state
+	oldRecord, err := findRecord(ctx, r.provider.client, id)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating record "+state.ID.Value,
-			err.Error(),
-		)
+		addClientError(&resp.Diagnostics, "Error reading record "+state.ID.Value, err)
+		return
+	}
+
+	logDebug(ctx, "Updating record", map[string]interface{}{"old_value": oldRecord.Value, "new_value": newRecord.Value})
+
+	if sameName(oldRecord.Name, newRecord.Name) && sameValue(newRecord.Type, oldRecord.Value, newRecord.Value) &&
+		oldRecord.TTL == newRecord.TTL && oldRecord.Priority == newRecord.Priority {
+		logDebug(ctx, "Record already up to date in Freenom", nil)
+	} else {
+		err = r.provider.client.ModifyRecord(ctx, domain, oldRecord, newRecord)
+
+		if err != nil {
+			addClientError(&resp.Diagnostics, "Error updating record "+state.ID.Value, err)
+			return
+		}
+	}
+
+	plan.ID = types.String{Value: computeID(domain, plan.Name.Value, plan.Type.Value, plan.Value.Value)}
+	plan.FQDN = types.String{Value: computeFQDN(domain, plan.Name.Value)}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 }
@@ -318,45 +467,201 @@ func (r *dnsRecordResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	var state FreenomDnsRecord
+	var state FreenomDnsRecordResource
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	domain, name, err := parseID(state.ID.Value)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.delete())
+	defer cancel()
+
+	id, err := stateRecordID(&state)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error parsing id"+state.ID.Value,
+			"Error parsing id "+state.ID.Value,
 			err.Error(),
 		)
 		return
 	}
 
-	log.Println("[INFO] Reading record ", state.ID.Value, domain, name)
+	ctx = logContext(ctx, "delete", id.Domain, map[string]interface{}{"name": id.Name, "type": id.Type})
+	logDebug(ctx, "Deleting record", nil)
 
-	record, err := getRecordByName(domain, name, &resp.Diagnostics)
+	record, err := findRecord(ctx, r.provider.client, id)
 
+	if errors.Is(err, client.ErrRecordNotFound) {
+		logDebug(ctx, "Record already deleted", nil)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
+		addClientError(&resp.Diagnostics, "Error reading record "+state.ID.Value, err)
+		return
+	}
+
+	err = r.provider.client.DeleteRecord(ctx, id.Domain, record)
+
+	if errors.Is(err, client.ErrRecordNotFound) {
+		logDebug(ctx, "Record already deleted", nil)
+	} else if err != nil {
+		addClientError(&resp.Diagnostics, "Error deleting record", err)
 		return
 	}
 
-	err = freenom.DeleteRecord(domain, record)
+	resp.State.RemoveResource(ctx)
+}
+
+// stateRecordID returns the id of the record of the state.
+// The legacy ids only have the name, so the type and the value of the state complete them when known.
+func stateRecordID(state *FreenomDnsRecordResource) (recordID, error) {
+	id, err := parseID(state.ID.Value)
 
 	if err != nil {
+		return id, err
+	}
+
+	if id.Type == "" && !state.Type.Null && !state.Type.Unknown && state.Type.Value != "" {
+		id.Type = state.Type.Value
+	}
+	if id.ValueHash == "" && !state.Value.Null && !state.Value.Unknown && state.Value.Value != "" {
+		id.ValueHash = hashValue(state.Type.Value, state.Value.Value)
+	}
+	return id, nil
+}
+
+// ImportState imports a record from <domain>/<name>/<type>/<value hash>, <domain>/<name>/<type>/<value>,
+// <domain>/<name>/<type>, <name>/<domain> or its fully qualified domain name
+func (r *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if r.provider.deferred {
+		addDeferredError(&resp.Diagnostics)
+		return
+	}
+
+	if !r.provider.configured {
 		resp.Diagnostics.AddError(
-			"Error deleting record",
-			err.Error(),
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
 		)
 		return
 	}
 
-	resp.State.RemoveResource(ctx)
+	ctx = logContext(ctx, "import", "", map[string]interface{}{"id": req.ID})
+	logDebug(ctx, "Importing record", nil)
+
+	domain, records, err := findImportRecords(ctx, r.provider.client, req.ID)
+
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Error importing record "+req.ID, err)
+		return
+	}
+
+	switch len(records) {
+	case 0:
+		resp.Diagnostics.AddError(
+			"Record not found",
+			fmt.Sprintf("No record of %s matches the import id %q.", domain, req.ID),
+		)
+		return
+	case 1:
+	default:
+		candidates := make([]string, 0, len(records))
+		for _, record := range records {
+			candidates = append(candidates, fmt.Sprintf("  %s (%s %s)", computeID(domain, record.Name, record.Type, record.Value), record.Type, record.Value))
+		}
+		resp.Diagnostics.AddError(
+			"Ambiguous import id",
+			fmt.Sprintf("The import id %q matches several records, import one of them by its id:\n%s", req.ID, strings.Join(candidates, "\n")),
+		)
+		return
+	}
+
+	record := records[0]
+	state := FreenomDnsRecordResource{
+		ID:       types.String{Value: computeID(domain, record.Name, record.Type, record.Value)},
+		Domain:   types.String{Value: domain},
+		Type:     types.String{Value: record.Type},
+		Name:     types.String{Value: importName(record.Name)},
+		Value:    types.String{Value: record.Value},
+		Priority: types.Int64{Value: int64(record.Priority)},
+		TTL:      types.Int64{Value: int64(record.TTL)},
+		FQDN:     types.String{Value: computeFQDN(domain, record.Name)},
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
-// Import resource
-func (r *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// findImportRecords returns the domain of an import id and its records matching the id
+func findImportRecords(ctx context.Context, c FreenomClient, id string) (string, []*client.DomainRecord, error) {
+	if !strings.Contains(id, "/") {
+		return findRecordsByFQDN(ctx, c, id)
+	}
+
+	var domain, name, recordType, value string
+
+	// the value is last, as it may contain slashes
+	parts := strings.SplitN(id, "/", 4)
+	switch len(parts) {
+	case 2:
+		name, domain = parts[0], parts[1]
+	case 3:
+		domain, name, recordType = parts[0], parts[1], parts[2]
+	case 4:
+		domain, name, recordType, value = parts[0], parts[1], parts[2], parts[3]
+	}
+
+	domainInfo, err := c.GetDomainInfo(ctx, domain)
+
+	if err != nil {
+		return domain, nil, err
+	}
+	return domain, matchImportRecords(domainInfo.Records, name, recordType, value), nil
+}
+
+// findRecordsByFQDN finds the domain of the account the name belongs to, trying its longest suffixes first
+func findRecordsByFQDN(ctx context.Context, c FreenomClient, fqdn string) (string, []*client.DomainRecord, error) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(fqdn, ".")), ".")
+
+	// the name is the whole fqdn for the apex
+	for i := 0; i < len(labels)-1; i++ {
+		name := strings.Join(labels[:i], ".")
+		domain := strings.Join(labels[i:], ".")
+
+		domainInfo, err := c.GetDomainInfo(ctx, domain)
+
+		if errors.Is(err, client.ErrDomainNotFound) {
+			continue
+		}
+		if err != nil {
+			return domain, nil, err
+		}
+		return domain, matchImportRecords(domainInfo.Records, name, "", ""), nil
+	}
+	return "", nil, fmt.Errorf("%s: %w", fqdn, client.ErrDomainNotFound)
+}
+
+// importName returns the name of an imported record, "@" for the apex
+func importName(name string) string {
+	if normalizeName(name) == "" {
+		return apexName
+	}
+	return strings.ToLower(name)
+}
+
+// matchImportRecords returns the records with the name, and the type and value or value hash when set
+func matchImportRecords(records []*client.DomainRecord, name, recordType, value string) []*client.DomainRecord {
+	var matches []*client.DomainRecord
+	for _, record := range records {
+		if !sameName(record.Name, name) || (recordType != "" && !strings.EqualFold(record.Type, recordType)) {
+			continue
+		}
+		if value != "" && !sameValue(record.Type, record.Value, value) && hashValue(record.Type, record.Value) != value {
+			continue
+		}
+		matches = append(matches, record)
+	}
+	return matches
 }
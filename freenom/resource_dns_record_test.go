@@ -1,9 +1,16 @@
 package freenom
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"terraform-provider-frenom/freenom/client"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -52,7 +59,853 @@ resource "freenom_dns_record" "test" {
     name = "%s"
     value = "%s"
     ttl = 3600
-    priority = 0
 }
 `, subdomain, ip)
 }
+
+func newTestDnsRecordResource(t *testing.T, c FreenomClient) (*dnsRecordResource, tfsdk.Schema) {
+	t.Helper()
+
+	r := &dnsRecordResource{provider: newTestProvider(c)}
+
+	schema, diags := r.GetSchema(context.Background())
+	checkNoErrors(t, diags)
+
+	return r, schema
+}
+
+func testDnsRecordState(t *testing.T, schema tfsdk.Schema, name, value string) tfsdk.State {
+	return testState(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Value: computeID("example.tk", name, "A", value)},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: name},
+		Value:    types.String{Value: value},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: computeFQDN("example.tk", name)},
+	})
+}
+
+func TestDnsRecordResourceCreate(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake()
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.CreateRequest{
+		Plan: testPlan(t, schema, &FreenomDnsRecordResource{
+			ID:       types.String{Unknown: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Value: "A"},
+			Name:     types.String{Value: "WWW"},
+			Value:    types.String{Value: "10.10.10.10"},
+			Priority: types.Int64{Value: 0},
+			TTL:      types.Int64{Value: 3600},
+			FQDN:     types.String{Unknown: true},
+		}),
+	}
+	resp := fwresource.CreateResponse{State: testState(t, schema, nil)}
+
+	r.Create(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 1 || records[0].Name != "www" || records[0].Value != "10.10.10.10" || records[0].TTL != 3600 {
+		t.Fatalf("unexpected records after create: %+v", records)
+	}
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.ID.Value != "example.tk/www/A/"+hashValue("A", "10.10.10.10") {
+		t.Errorf("unexpected id %q", state.ID.Value)
+	}
+	if state.FQDN.Value != "www.example.tk" {
+		t.Errorf("unexpected fqdn %q", state.FQDN.Value)
+	}
+}
+
+func TestDnsRecordResourceRead(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 300})
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.TTL.Value != 300 {
+		t.Errorf("expected the state to be refreshed, got %+v", state)
+	}
+}
+
+func TestDnsRecordResourceReadSharedName(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.9", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 300},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.Type.Value != "A" || state.Value.Value != "10.10.10.10" || state.TTL.Value != 300 {
+		t.Errorf("expected the record with the type and value of the state, got %+v", state)
+	}
+}
+
+func TestDnsRecordResourceReadLegacyID(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 300},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.ReadRequest{State: testState(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Value: "www/example.tk"},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "10.10.10.10"},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: "www.example.tk"},
+	})}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.ID.Value != computeID("example.tk", "www", "A", "10.10.10.10") {
+		t.Errorf("expected the legacy id to be upgraded, got %q", state.ID.Value)
+	}
+	if state.TTL.Value != 300 {
+		t.Errorf("expected the record with the type and value of the state, got %+v", state)
+	}
+}
+
+func TestDnsRecordResourceReadLegacyIDValueChanged(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	// the value was changed in the Freenom UI since the legacy id was written
+	req := fwresource.ReadRequest{State: testState(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Value: "www/example.tk"},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "10.10.10.10"},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: "www.example.tk"},
+	})}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if resp.State.Raw.IsNull() {
+		t.Fatalf("expected the only A record of the name to be kept in the state")
+	}
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.ID.Value != computeID("example.tk", "www", "A", "10.10.10.11") || state.Value.Value != "10.10.10.11" {
+		t.Errorf("expected the record with its new value, got %+v", state)
+	}
+}
+
+func TestDnsRecordResourceReadLegacyIDValueChangedAmbiguous(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.12", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.ReadRequest{State: testState(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Value: "www/example.tk"},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "10.10.10.10"},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: "www.example.tk"},
+	})}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the record not to be picked among several records with its name and type")
+	}
+}
+
+func TestDnsRecordResourceReadValueChanged(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	// the value was changed in the Freenom UI since the record was created
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if resp.State.Raw.IsNull() {
+		t.Fatalf("expected the only A record of the name to be kept in the state")
+	}
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &state))
+
+	if state.ID.Value != computeID("example.tk", "www", "A", "10.10.10.11") || state.Value.Value != "10.10.10.11" {
+		t.Errorf("expected the record with its new value, got %+v", state)
+	}
+}
+
+func TestDnsRecordResourceReadNotFound(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, newTestFake())
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the missing record to be removed from the state")
+	}
+}
+
+// unrecognizedPageClient reads every domain as a page Freenom changed
+type unrecognizedPageClient struct {
+	*client.Fake
+}
+
+func (c *unrecognizedPageClient) GetDomainInfo(ctx context.Context, domain string) (*client.DomainInfo, error) {
+	return nil, fmt.Errorf("reading domain %s: %w: no DNS management form in the page", domain, client.ErrParse)
+}
+
+func TestDnsRecordResourceReadUnrecognizedPage(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, &unrecognizedPageClient{Fake: newTestFake()})
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Unexpected Freenom page" {
+		t.Fatalf("expected an unexpected page error, got %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.Equal(req.State.Raw) {
+		t.Errorf("expected the record to be kept in the state")
+	}
+}
+
+func TestDnsRecordResourceReadUnknownDomain(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, client.NewFake())
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error reading a record of a missing domain")
+	}
+}
+
+func TestDnsRecordResourceReadDeferred(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, nil)
+	r.provider = &freenomProvider{deferred: true}
+
+	req := fwresource.ReadRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if !resp.State.Raw.Equal(req.State.Raw) {
+		t.Errorf("expected the prior state to be kept until the provider configuration is known")
+	}
+}
+
+func TestDnsRecordResourceUpdate(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600})
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	state := testDnsRecordState(t, schema, "www", "10.10.10.10")
+	plan := testDnsRecordState(t, schema, "www", "20.20.20.20")
+
+	req := fwresource.UpdateRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}
+	resp := fwresource.UpdateResponse{State: state}
+
+	r.Update(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 1 || records[0].Value != "20.20.20.20" {
+		t.Fatalf("unexpected records after update: %+v", records)
+	}
+
+	var updated FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &updated))
+
+	if updated.Value.Value != "20.20.20.20" || updated.ID.Value != computeID("example.tk", "www", "A", "20.20.20.20") {
+		t.Errorf("expected the state to be updated, got %+v", updated)
+	}
+}
+
+func TestDnsRecordResourceUpdateSharedName(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.9", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	state := testDnsRecordState(t, schema, "www", "10.10.10.10")
+	plan := testDnsRecordState(t, schema, "api", "10.10.10.10")
+
+	req := fwresource.UpdateRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}
+	resp := fwresource.UpdateResponse{State: state}
+
+	r.Update(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 2 || records[0].Name != "www" || records[1].Name != "api" {
+		t.Fatalf("unexpected records after update: %+v", records)
+	}
+
+	var updated FreenomDnsRecordResource
+	checkNoErrors(t, resp.State.Get(ctx, &updated))
+
+	if updated.FQDN.Value != "api.example.tk" || updated.ID.Value != computeID("example.tk", "api", "A", "10.10.10.10") {
+		t.Errorf("expected the state to be updated, got %+v", updated)
+	}
+}
+
+func TestDnsRecordResourceModifyPlan(t *testing.T) {
+	tests := map[string]struct {
+		name, value        string
+		idKnown, fqdnKnown bool
+	}{
+		"ttl":   {name: "www", value: "10.10.10.10", idKnown: true, fqdnKnown: true},
+		"value": {name: "www", value: "20.20.20.20", fqdnKnown: true},
+		"name":  {name: "api", value: "10.10.10.10"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, schema := newTestDnsRecordResource(t, newTestFake())
+
+			state := testDnsRecordState(t, schema, "www", "10.10.10.10")
+			planned := testDnsRecordState(t, schema, test.name, test.value)
+			plan := tfsdk.Plan{Schema: planned.Schema, Raw: planned.Raw}
+
+			resp := fwresource.ModifyPlanResponse{Plan: plan}
+			config := tfsdk.Config{Schema: planned.Schema, Raw: planned.Raw}
+
+			r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plan, Config: config}, &resp)
+			checkNoErrors(t, resp.Diagnostics)
+
+			var modified FreenomDnsRecordResource
+			checkNoErrors(t, resp.Plan.Get(ctx, &modified))
+
+			if modified.ID.Unknown == test.idKnown {
+				t.Errorf("expected the id known %v, got %+v", test.idKnown, modified.ID)
+			}
+			if modified.FQDN.Unknown == test.fqdnKnown {
+				t.Errorf("expected the fqdn known %v, got %+v", test.fqdnKnown, modified.FQDN)
+			}
+		})
+	}
+}
+
+func TestDnsRecordResourceDelete(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.9", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "mail", Value: "10.10.10.11", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.DeleteRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.DeleteResponse{State: req.State}
+
+	r.Delete(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 2 || records[0].Value != "10.10.10.9" || records[1].Name != "mail" {
+		t.Fatalf("unexpected records after delete: %+v", records)
+	}
+
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the resource to be removed from the state")
+	}
+}
+
+func TestDnsRecordResourceDeleteValueChanged(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600},
+		client.DomainRecord{Type: "A", Name: "mail", Value: "10.10.10.12", TTL: 3600},
+	)
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.DeleteRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.DeleteResponse{State: req.State}
+
+	r.Delete(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 1 || records[0].Name != "mail" {
+		t.Fatalf("expected the record with its changed value to be deleted, got %+v", records)
+	}
+}
+
+func TestDnsRecordResourceDeleteNotFound(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, newTestFake())
+
+	req := fwresource.DeleteRequest{State: testDnsRecordState(t, schema, "www", "10.10.10.10")}
+	resp := fwresource.DeleteResponse{State: req.State}
+
+	r.Delete(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the resource to be removed from the state")
+	}
+}
+
+// failingClient fails every write with err
+type failingClient struct {
+	*client.Fake
+
+	err error
+}
+
+func (c *failingClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	return c.err
+}
+
+func TestDnsRecordResourceCreateAuthenticationError(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, &failingClient{
+		Fake: newTestFake(),
+		err:  fmt.Errorf("adding records: %w", client.ErrAuthentication),
+	})
+
+	plan := testDnsRecordState(t, schema, "www", "10.10.10.10")
+	req := fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}
+	resp := fwresource.CreateResponse{State: testState(t, schema, nil)}
+
+	r.Create(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Freenom authentication failed" {
+		t.Fatalf("expected an authentication error, got %v", resp.Diagnostics)
+	}
+}
+
+// hangingClient never completes a write before ctx is done
+type hangingClient struct {
+	*client.Fake
+}
+
+func (c *hangingClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	<-ctx.Done()
+	return fmt.Errorf("adding records: %w", ctx.Err())
+}
+
+func TestDnsRecordResourceCreateTimeout(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, &hangingClient{Fake: newTestFake()})
+
+	req := fwresource.CreateRequest{
+		Plan: testPlan(t, schema, &FreenomDnsRecordResource{
+			ID:       types.String{Unknown: true},
+			Domain:   types.String{Value: "example.tk"},
+			Type:     types.String{Value: "A"},
+			Name:     types.String{Value: "www"},
+			Value:    types.String{Value: "10.10.10.10"},
+			Priority: types.Int64{Value: 0},
+			TTL:      types.Int64{Value: 3600},
+			FQDN:     types.String{Unknown: true},
+			Timeouts: &Timeouts{
+				Create: types.String{Value: "10ms"},
+				Read:   types.String{Null: true},
+				Update: types.String{Null: true},
+				Delete: types.String{Null: true},
+			},
+		}),
+	}
+	resp := fwresource.CreateResponse{State: testState(t, schema, nil)}
+
+	done := make(chan struct{})
+	go func() {
+		r.Create(ctx, req, &resp)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the create to fail after its timeout")
+	}
+
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "timeouts block") {
+		t.Fatalf("expected a timeout error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestDnsRecordResourceImportState(t *testing.T) {
+	fake := newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 300},
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 300},
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 300},
+		client.DomainRecord{Type: "TXT", Name: "api", Value: "path=/v1", TTL: 3600},
+	)
+
+	tests := map[string]struct {
+		id       string
+		expected string
+		err      string
+	}{
+		"value":      {id: "example.tk/www/A/10.10.10.11", expected: "10.10.10.11"},
+		"value hash": {id: computeID("example.tk", "www", "A", "10.10.10.11"), expected: "10.10.10.11"},
+		"type":       {id: "example.tk/www/AAAA", expected: "2001:db8::1"},
+		"slash":      {id: "example.tk/api/TXT/path=/v1", expected: "path=/v1"},
+		"legacy":     {id: "api/example.tk", expected: "path=/v1"},
+		"fqdn":       {id: "API.example.tk.", expected: "path=/v1"},
+		"ambiguous":  {id: "example.tk/www/A", err: "Ambiguous import id"},
+		"not found":  {id: "example.tk/mail/MX", err: "Record not found"},
+		"no domain":  {id: "www.example.ml", err: "Domain not found"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, schema := newTestDnsRecordResource(t, fake)
+
+			resp := fwresource.ImportStateResponse{State: testState(t, schema, nil)}
+			r.ImportState(ctx, fwresource.ImportStateRequest{ID: test.id}, &resp)
+
+			if test.err != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != test.err {
+					t.Fatalf("expected the error %q, got %v", test.err, resp.Diagnostics)
+				}
+				return
+			}
+			checkNoErrors(t, resp.Diagnostics)
+
+			var state FreenomDnsRecordResource
+			checkNoErrors(t, resp.State.Get(ctx, &state))
+
+			if state.Value.Value != test.expected || state.Domain.Value != "example.tk" {
+				t.Errorf("unexpected imported record %+v", state)
+			}
+			if state.ID.Value != computeID("example.tk", state.Name.Value, state.Type.Value, state.Value.Value) {
+				t.Errorf("unexpected id %q", state.ID.Value)
+			}
+		})
+	}
+}
+
+func TestDnsRecordResourceImportStateAmbiguousCandidates(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, newTestFake(
+		client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 300},
+		client.DomainRecord{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 300},
+	))
+
+	resp := fwresource.ImportStateResponse{State: testState(t, schema, nil)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "www.example.tk"}, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error importing an ambiguous id")
+	}
+
+	detail := resp.Diagnostics.Errors()[0].Detail()
+	for _, id := range []string{computeID("example.tk", "www", "A", "10.10.10.10"), computeID("example.tk", "www", "AAAA", "2001:db8::1")} {
+		if !strings.Contains(detail, id) {
+			t.Errorf("expected the candidate %s in %q", id, detail)
+		}
+	}
+}
+
+func TestDnsRecordResourceApex(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(client.DomainRecord{Type: "A", Name: "www", Value: "10.10.10.11", TTL: 3600})
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	createResp := fwresource.CreateResponse{State: testState(t, schema, nil)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: testPlan(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Unknown: true},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: "@"},
+		Value:    types.String{Value: "10.10.10.10"},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Unknown: true},
+	})}, &createResp)
+	checkNoErrors(t, createResp.Diagnostics)
+
+	records := fake.Records("example.tk")
+	if len(records) != 2 || records[1].Name != "" {
+		t.Fatalf("expected the apex record to have an empty name, got %+v", records)
+	}
+
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	checkNoErrors(t, readResp.Diagnostics)
+
+	var state FreenomDnsRecordResource
+	checkNoErrors(t, readResp.State.Get(ctx, &state))
+
+	if state.Name.Value != "@" || state.FQDN.Value != "example.tk" || state.ID.Value != "example.tk/@/A/"+hashValue("A", "10.10.10.10") {
+		t.Errorf("unexpected apex record state %+v", state)
+	}
+
+	importResp := fwresource.ImportStateResponse{State: testState(t, schema, nil)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "example.tk"}, &importResp)
+	checkNoErrors(t, importResp.Diagnostics)
+
+	var imported FreenomDnsRecordResource
+	checkNoErrors(t, importResp.State.Get(ctx, &imported))
+
+	if imported.ID.Value != state.ID.Value || imported.Name.Value != "@" {
+		t.Errorf("expected the apex record to be imported, got %+v", imported)
+	}
+}
+
+func TestDnsRecordResourceModifyPlanDefaults(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, newTestFake())
+	r.provider.defaultTTL = 300
+
+	record := &FreenomDnsRecordResource{
+		ID:       types.String{Null: true},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "A"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "10.10.10.10"},
+		Priority: types.Int64{Null: true},
+		TTL:      types.Int64{Null: true},
+		FQDN:     types.String{Null: true},
+	}
+	config := testConfig(t, schema, record)
+
+	record.ID = types.String{Unknown: true}
+	record.Priority = types.Int64{Unknown: true}
+	record.TTL = types.Int64{Unknown: true}
+	record.FQDN = types.String{Unknown: true}
+	plan := testPlan(t, schema, record)
+
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: testState(t, schema, nil), Plan: plan, Config: config}, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var modified FreenomDnsRecordResource
+	checkNoErrors(t, resp.Plan.Get(ctx, &modified))
+
+	if modified.TTL.Value != 300 || modified.TTL.Unknown || modified.Priority.Value != 0 || modified.Priority.Unknown {
+		t.Errorf("expected the default ttl and priority, got %+v", modified)
+	}
+}
+
+func TestDnsRecordResourceValidateConfig(t *testing.T) {
+	tests := map[string]struct {
+		recordType string
+		priority   types.Int64
+		err        string
+	}{
+		"A":              {recordType: "A", priority: types.Int64{Null: true}},
+		"A priority":     {recordType: "A", priority: types.Int64{Value: 0}, err: "Unexpected priority"},
+		"MX":             {recordType: "MX", priority: types.Int64{Value: 10}},
+		"MX unknown":     {recordType: "MX", priority: types.Int64{Unknown: true}},
+		"MX no priority": {recordType: "MX", priority: types.Int64{Null: true}, err: "Missing priority"},
+		"TXT unknown":    {recordType: "TXT", priority: types.Int64{Unknown: true}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, schema := newTestDnsRecordResource(t, newTestFake())
+
+			config := testConfig(t, schema, &FreenomDnsRecordResource{
+				ID:       types.String{Null: true},
+				Domain:   types.String{Value: "example.tk"},
+				Type:     types.String{Value: test.recordType},
+				Name:     types.String{Value: "www"},
+				Value:    types.String{Value: "10.10.10.10"},
+				Priority: test.priority,
+				TTL:      types.Int64{Null: true},
+				FQDN:     types.String{Null: true},
+			})
+
+			resp := fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: config}, &resp)
+
+			if test.err == "" {
+				checkNoErrors(t, resp.Diagnostics)
+				return
+			}
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != test.err {
+				t.Errorf("expected the error %q, got %v", test.err, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDnsRecordResourceConfigValidators(t *testing.T) {
+	tests := map[string]struct {
+		recordType string
+		value      types.String
+		valid      bool
+	}{
+		"A":           {recordType: "A", value: types.String{Value: "10.10.10.10"}, valid: true},
+		"A invalid":   {recordType: "A", value: types.String{Value: "10.10.10"}},
+		"AAAA as A":   {recordType: "A", value: types.String{Value: "2001:db8::1"}},
+		"CNAME":       {recordType: "CNAME", value: types.String{Value: "www.example.tk."}, valid: true},
+		"unknown":     {recordType: "A", value: types.String{Unknown: true}, valid: true},
+		"MX invalid":  {recordType: "MX", value: types.String{Value: "10 mail.example.tk"}},
+		"TXT empty":   {recordType: "TXT", value: types.String{Value: ""}},
+		"TXT a value": {recordType: "TXT", value: types.String{Value: "v=spf1 -all"}, valid: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r, schema := newTestDnsRecordResource(t, newTestFake())
+
+			config := testConfig(t, schema, &FreenomDnsRecordResource{
+				ID:       types.String{Null: true},
+				Domain:   types.String{Value: "example.tk"},
+				Type:     types.String{Value: test.recordType},
+				Name:     types.String{Value: "www"},
+				Value:    test.value,
+				Priority: types.Int64{Null: true},
+				TTL:      types.Int64{Null: true},
+				FQDN:     types.String{Null: true},
+			})
+
+			resp := fwresource.ValidateConfigResponse{}
+			for _, validator := range r.ConfigValidators(ctx) {
+				validator.ValidateResource(ctx, fwresource.ValidateConfigRequest{Config: config}, &resp)
+			}
+
+			if test.valid {
+				checkNoErrors(t, resp.Diagnostics)
+				return
+			}
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid record value" {
+				t.Errorf("expected an invalid record value error, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDnsRecordResourceReadEquivalentValue(t *testing.T) {
+	ctx := context.Background()
+	fake := newTestFake(client.DomainRecord{Type: "CNAME", Name: "www", Value: "API.example.tk", TTL: 3600})
+	r, schema := newTestDnsRecordResource(t, fake)
+
+	req := fwresource.ReadRequest{State: testState(t, schema, &FreenomDnsRecordResource{
+		ID:       types.String{Value: computeID("example.tk", "www", "CNAME", "api.example.tk.")},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "CNAME"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "api.example.tk."},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: "www.example.tk"},
+	})}
+	resp := fwresource.ReadResponse{State: req.State}
+
+	r.Read(ctx, req, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	if !resp.State.Raw.Equal(req.State.Raw) {
+		t.Errorf("expected the equivalent value to keep the state unchanged")
+	}
+}
+
+func TestDnsRecordResourceModifyPlanEquivalentValue(t *testing.T) {
+	ctx := context.Background()
+	r, schema := newTestDnsRecordResource(t, newTestFake())
+
+	record := &FreenomDnsRecordResource{
+		ID:       types.String{Value: computeID("example.tk", "www", "AAAA", "2001:db8::1")},
+		Domain:   types.String{Value: "example.tk"},
+		Type:     types.String{Value: "AAAA"},
+		Name:     types.String{Value: "www"},
+		Value:    types.String{Value: "2001:db8::1"},
+		Priority: types.Int64{Value: 0},
+		TTL:      types.Int64{Value: 3600},
+		FQDN:     types.String{Value: "www.example.tk"},
+	}
+	state := testState(t, schema, record)
+
+	record.Value = types.String{Value: "2001:DB8:0:0:0:0:0:1"}
+	planned := testState(t, schema, record)
+	plan := tfsdk.Plan{Schema: planned.Schema, Raw: planned.Raw}
+	config := tfsdk.Config{Schema: planned.Schema, Raw: planned.Raw}
+
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plan, Config: config}, &resp)
+	checkNoErrors(t, resp.Diagnostics)
+
+	var modified FreenomDnsRecordResource
+	checkNoErrors(t, resp.Plan.Get(ctx, &modified))
+
+	if modified.ID.Unknown || modified.ID.Value != computeID("example.tk", "www", "AAAA", "2001:DB8:0:0:0:0:0:1") {
+		t.Errorf("expected the id of an equivalent value to be kept, got %+v", modified.ID)
+	}
+}
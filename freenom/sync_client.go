@@ -0,0 +1,288 @@
+package freenom
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"terraform-provider-frenom/freenom/client"
+	"time"
+)
+
+var _ FreenomClient = &syncClient{}
+
+// syncClient serializes the mutations of every domain.
+// Freenom sends back all the records of the domain on every change, so two concurrent
+// writes to the same domain overwrite each other. Writes to different domains still run concurrently.
+//
+// Record creations arriving within batchWindow are queued per domain and sent in a single AddRecord.
+type syncClient struct {
+	FreenomClient
+
+	batchWindow time.Duration
+
+	mu sync.Mutex
+	// locks holds a semaphore of capacity 1 per domain, unlike a mutex acquiring it can be canceled
+	locks   map[string]chan struct{}
+	batches map[string]*createBatch
+}
+
+// createBatch collects the record creations of a domain waiting to be sent
+type createBatch struct {
+	pending []*pendingCreate
+}
+
+type pendingCreate struct {
+	// ctx is the context of the caller, the creation is dropped when it is done before the batch is sent
+	ctx     context.Context
+	records []client.DomainRecord
+	result  chan error
+}
+
+func newSyncClient(c FreenomClient, batchWindow time.Duration) *syncClient {
+	return &syncClient{
+		FreenomClient: c,
+		batchWindow:   batchWindow,
+		locks:         make(map[string]chan struct{}),
+		batches:       make(map[string]*createBatch),
+	}
+}
+
+// lock acquires the lock of the domain and returns the function releasing it.
+// It gives up with the error of ctx when ctx is done first.
+func (c *syncClient) lock(ctx context.Context, domain string) (func(), error) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = make(chan struct{}, 1)
+		c.locks[key] = l
+	}
+	c.mu.Unlock()
+
+	select {
+	case l <- struct{}{}:
+		return func() { <-l }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh re-reads the domain, so the mutation is based on its latest records
+func (c *syncClient) refresh(ctx context.Context, domain string) error {
+	_, err := c.FreenomClient.GetDomainInfo(ctx, domain)
+	return err
+}
+
+// AddRecord queues the records in the batch of the domain and waits for the batch to be sent.
+// When ctx is done while the records are queued they are not sent, the batch still sends the records of the other callers.
+func (c *syncClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	if c.batchWindow <= 0 {
+		unlock, err := c.lock(ctx, domain)
+
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := c.refresh(ctx, domain); err != nil {
+			return err
+		}
+		return c.FreenomClient.AddRecord(ctx, domain, records)
+	}
+
+	key := strings.ToLower(domain)
+	create := &pendingCreate{
+		ctx:     ctx,
+		records: records,
+		result:  make(chan error, 1),
+	}
+
+	c.mu.Lock()
+	batch, ok := c.batches[key]
+	if !ok {
+		batch = &createBatch{}
+		c.batches[key] = batch
+		time.AfterFunc(c.batchWindow, func() { c.flush(domain) })
+	}
+	batch.pending = append(batch.pending, create)
+	c.mu.Unlock()
+
+	select {
+	case err := <-create.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush sends the queued creations of the domain in a single AddRecord.
+// When Freenom rejects the batch, the creations which did not make it are sent one by one,
+// so every caller gets back its own result.
+func (c *syncClient) flush(domain string) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	batch := c.batches[key]
+	delete(c.batches, key)
+	c.mu.Unlock()
+
+	ctx, cancel := batchContext(batch.pending)
+	defer cancel()
+
+	unlock, err := c.lock(ctx, domain)
+
+	if err != nil {
+		for _, create := range batch.pending {
+			create.result <- err
+		}
+		return
+	}
+	defer unlock()
+
+	// the callers which gave up already returned, creating their records would leave them out of the state
+	var pending []*pendingCreate
+	for _, create := range batch.pending {
+		if err := create.ctx.Err(); err != nil {
+			create.result <- err
+			continue
+		}
+		pending = append(pending, create)
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	before, err := c.FreenomClient.GetDomainInfo(ctx, domain)
+
+	if err != nil {
+		for _, create := range pending {
+			create.result <- err
+		}
+		return
+	}
+
+	var records []client.DomainRecord
+	for _, create := range pending {
+		records = append(records, create.records...)
+	}
+
+	batchErr := c.FreenomClient.AddRecord(ctx, domain, records)
+
+	if batchErr == nil {
+		for _, create := range pending {
+			create.result <- nil
+		}
+		return
+	}
+
+	if len(pending) == 1 {
+		pending[0].result <- batchErr
+		return
+	}
+
+	after, err := c.FreenomClient.GetDomainInfo(ctx, domain)
+
+	for _, create := range pending {
+		if err != nil {
+			create.result <- err
+			continue
+		}
+
+		// records which were already there before the batch have not been created by it
+		if containsRecords(after.Records, create.records) && !containsRecords(before.Records, create.records) {
+			create.result <- nil
+			continue
+		}
+
+		create.result <- c.FreenomClient.AddRecord(create.ctx, domain, create.records)
+	}
+}
+
+// batchContext returns the context sending a batch, bounded by the latest deadline of the callers
+// and canceled once none of them waits anymore
+func batchContext(pending []*pendingCreate) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var latest time.Time
+	for _, create := range pending {
+		deadline, ok := create.ctx.Deadline()
+		if !ok {
+			latest = time.Time{}
+			break
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	if !latest.IsZero() {
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithDeadline(ctx, latest)
+		cancelAll := cancel
+		cancel = func() {
+			cancelDeadline()
+			cancelAll()
+		}
+	}
+
+	go func() {
+		for _, create := range pending {
+			select {
+			case <-create.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+
+	return ctx, cancel
+}
+
+func (c *syncClient) ModifyRecord(ctx context.Context, domain string, oldRecord, newRecord *client.DomainRecord) error {
+	unlock, err := c.lock(ctx, domain)
+
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := c.refresh(ctx, domain); err != nil {
+		return err
+	}
+	return c.FreenomClient.ModifyRecord(ctx, domain, oldRecord, newRecord)
+}
+
+func (c *syncClient) DeleteRecord(ctx context.Context, domain string, record *client.DomainRecord) error {
+	unlock, err := c.lock(ctx, domain)
+
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := c.refresh(ctx, domain); err != nil {
+		return err
+	}
+	return c.FreenomClient.DeleteRecord(ctx, domain, record)
+}
+
+// containsRecords reports whether all the records are in existing
+func containsRecords(existing []*client.DomainRecord, records []client.DomainRecord) bool {
+	for _, record := range records {
+		found := false
+		for _, r := range existing {
+			if strings.EqualFold(r.Type, record.Type) && strings.EqualFold(r.Name, record.Name) && r.Value == record.Value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,315 @@
+package freenom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+	"time"
+)
+
+// overlapClient records how many writes run at the same time on every domain
+type overlapClient struct {
+	*client.Fake
+
+	mu       sync.Mutex
+	inFlight map[string]int
+	maxSeen  map[string]int
+	reads    int
+}
+
+func newOverlapClient(fake *client.Fake) *overlapClient {
+	return &overlapClient{
+		Fake:     fake,
+		inFlight: make(map[string]int),
+		maxSeen:  make(map[string]int),
+	}
+}
+
+func (c *overlapClient) GetDomainInfo(ctx context.Context, domain string) (*client.DomainInfo, error) {
+	c.mu.Lock()
+	c.reads++
+	c.mu.Unlock()
+
+	return c.Fake.GetDomainInfo(ctx, domain)
+}
+
+func (c *overlapClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	c.mu.Lock()
+	c.inFlight[domain]++
+	if c.inFlight[domain] > c.maxSeen[domain] {
+		c.maxSeen[domain] = c.inFlight[domain]
+	}
+	c.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight[domain]--
+	c.mu.Unlock()
+
+	return c.Fake.AddRecord(ctx, domain, records)
+}
+
+func TestSyncClientSerializesWritesPerDomain(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk")
+	fake.AddDomain("example.ml")
+
+	overlap := newOverlapClient(fake)
+	c := newSyncClient(overlap, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, domain := range []string{"example.tk", "example.ml"} {
+			wg.Add(1)
+			go func(domain string, i int) {
+				defer wg.Done()
+
+				err := c.AddRecord(context.Background(), domain, []client.DomainRecord{
+					{Type: "A", Name: fmt.Sprintf("host%d", i), Value: "10.10.10.10", TTL: 3600},
+				})
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			}(domain, i)
+		}
+	}
+	wg.Wait()
+
+	for _, domain := range []string{"example.tk", "example.ml"} {
+		if overlap.maxSeen[domain] != 1 {
+			t.Errorf("expected writes to %s to be serialized, got %d at the same time", domain, overlap.maxSeen[domain])
+		}
+		if len(fake.Records(domain)) != 10 {
+			t.Errorf("expected 10 records in %s, got %d", domain, len(fake.Records(domain)))
+		}
+	}
+
+	if overlap.reads != 20 {
+		t.Errorf("expected the domain to be read before every write, got %d reads", overlap.reads)
+	}
+}
+
+// blockingClient blocks the write to example.tk until a write to example.ml starts
+type blockingClient struct {
+	*client.Fake
+
+	tkStarted chan struct{}
+	mlStarted chan struct{}
+}
+
+func (c *blockingClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	switch domain {
+	case "example.tk":
+		close(c.tkStarted)
+		select {
+		case <-c.mlStarted:
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("write to example.ml did not run concurrently")
+		}
+	case "example.ml":
+		close(c.mlStarted)
+	}
+	return c.Fake.AddRecord(ctx, domain, records)
+}
+
+func TestSyncClientDoesNotSerializeDifferentDomains(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk")
+	fake.AddDomain("example.ml")
+
+	blocking := &blockingClient{
+		Fake:      fake,
+		tkStarted: make(chan struct{}),
+		mlStarted: make(chan struct{}),
+	}
+	c := newSyncClient(blocking, 0)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.AddRecord(context.Background(), "example.tk", []client.DomainRecord{{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600}})
+	}()
+
+	// the write to example.tk is holding the lock of its domain
+	<-blocking.tkStarted
+
+	if err := c.AddRecord(context.Background(), "example.ml", []client.DomainRecord{{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+// countingClient counts the AddRecord calls and the records they carried
+type countingClient struct {
+	*client.Fake
+
+	mu      sync.Mutex
+	calls   int
+	records int
+}
+
+func (c *countingClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	c.mu.Lock()
+	c.calls++
+	c.records += len(records)
+	c.mu.Unlock()
+
+	return c.Fake.AddRecord(ctx, domain, records)
+}
+
+func addRecordsConcurrently(c FreenomClient, domain string, records []client.DomainRecord) []error {
+	errs := make([]error, len(records))
+
+	var wg sync.WaitGroup
+	for i := range records {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.AddRecord(context.Background(), domain, records[i:i+1])
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestSyncClientBatchesCreations(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk")
+
+	counting := &countingClient{Fake: fake}
+	c := newSyncClient(counting, 50*time.Millisecond)
+
+	var records []client.DomainRecord
+	for i := 0; i < 5; i++ {
+		records = append(records, client.DomainRecord{Type: "A", Name: fmt.Sprintf("host%d", i), Value: "10.10.10.10", TTL: 3600})
+	}
+
+	for i, err := range addRecordsConcurrently(c, "example.tk", records) {
+		if err != nil {
+			t.Errorf("unexpected error creating record %d: %s", i, err)
+		}
+	}
+
+	if counting.calls != 1 || counting.records != 5 {
+		t.Errorf("expected a single AddRecord with 5 records, got %d calls with %d records", counting.calls, counting.records)
+	}
+
+	if len(fake.Records("example.tk")) != 5 {
+		t.Errorf("expected 5 records, got %d", len(fake.Records("example.tk")))
+	}
+}
+
+func TestSyncClientBatchReportsFailuresPerCreation(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk", client.DomainRecord{Type: "A", Name: "taken", Value: "10.10.10.10", TTL: 3600})
+
+	counting := &countingClient{Fake: fake}
+	c := newSyncClient(counting, 50*time.Millisecond)
+
+	records := []client.DomainRecord{
+		{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600},
+		{Type: "A", Name: "taken", Value: "10.10.10.10", TTL: 3600},
+		{Type: "A", Name: "api", Value: "10.10.10.10", TTL: 3600},
+	}
+
+	errs := addRecordsConcurrently(c, "example.tk", records)
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("expected the valid records to be created, got %v", errs)
+	}
+	if errs[1] == nil {
+		t.Errorf("expected an error creating the duplicate record")
+	}
+
+	if len(fake.Records("example.tk")) != 3 {
+		t.Errorf("expected 3 records, got %+v", fake.Records("example.tk"))
+	}
+}
+
+func TestSyncClientDropsCreationsTimedOutWhileQueued(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk")
+
+	counting := &countingClient{Fake: fake}
+	c := newSyncClient(counting, 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- c.AddRecord(ctx, "example.tk", []client.DomainRecord{{Type: "A", Name: "late", Value: "10.10.10.10", TTL: 3600}})
+	}()
+	go func() {
+		errs <- c.AddRecord(context.Background(), "example.tk", []client.DomainRecord{{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600}})
+	}()
+
+	var timedOut, created int
+	for i := 0; i < 2; i++ {
+		switch err := <-errs; {
+		case errors.Is(err, context.DeadlineExceeded):
+			timedOut++
+		case err == nil:
+			created++
+		default:
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+
+	if timedOut != 1 || created != 1 {
+		t.Fatalf("expected a timed out and a created record, got %d and %d", timedOut, created)
+	}
+
+	records := fake.Records("example.tk")
+	if len(records) != 1 || records[0].Name != "www" {
+		t.Errorf("expected only the record of the waiting caller to be created, got %+v", records)
+	}
+	if counting.records != 1 {
+		t.Errorf("expected the timed out record not to be sent, got %d records sent", counting.records)
+	}
+}
+
+// waitingClient blocks every AddRecord until its context is done
+type waitingClient struct {
+	*client.Fake
+
+	canceled chan error
+}
+
+func (c *waitingClient) AddRecord(ctx context.Context, domain string, records []client.DomainRecord) error {
+	<-ctx.Done()
+	c.canceled <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestSyncClientBatchCanceledWithItsCallers(t *testing.T) {
+	fake := client.NewFake()
+	fake.AddDomain("example.tk")
+
+	waiting := &waitingClient{Fake: fake, canceled: make(chan error, 1)}
+	c := newSyncClient(waiting, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.AddRecord(ctx, "example.tk", []client.DomainRecord{{Type: "A", Name: "www", Value: "10.10.10.10", TTL: 3600}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the create to time out, got %v", err)
+	}
+
+	select {
+	case err := <-waiting.canceled:
+		if err == nil {
+			t.Errorf("expected the request of the batch to be canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the request of the batch to be canceled once its caller gave up")
+	}
+}
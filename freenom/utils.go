@@ -1,99 +1,285 @@
 package freenom
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"terraform-provider-frenom/freenom/client"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/tzwsoho/go-freenom/freenom"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-func parseID(id string) (string, string, error) {
+// recordID identifies a record by its domain, name, type and a hash of its value,
+// so records sharing a name are told apart.
+// The legacy ids (<name>/<domain>) only have the domain and the name.
+type recordID struct {
+	Domain    string
+	Name      string
+	Type      string
+	ValueHash string
+}
+
+// parseID parses the ids <domain>/<name>/<type>/<value hash> and the legacy ids <name>/<domain>
+func parseID(id string) (recordID, error) {
 	parts := strings.Split(id, "/")
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid id: %s", id)
+
+	switch len(parts) {
+	case 4:
+		return recordID{Domain: parts[0], Name: parts[1], Type: parts[2], ValueHash: parts[3]}, nil
+	case 2:
+		return recordID{Domain: parts[1], Name: parts[0]}, nil
 	}
-	return parts[1], parts[0], nil // return name and domain
+	return recordID{}, fmt.Errorf("invalid id %q, expected <domain>/<name>/<type>/<value hash>", id)
+}
+
+func computeID(domain, name, recordType, value string) string {
+	return recordID{Domain: domain, Name: name, Type: recordType, ValueHash: hashValue(recordType, value)}.String()
 }
 
-func computeID(domain, name string) string {
-	return fmt.Sprintf("%s/%s", strings.ToLower(name), domain)
+// hashValue returns a short stable hash of the value of a record, which may contain any character,
+// the same for the equivalent values
+func hashValue(recordType, value string) string {
+	sum := sha256.Sum256([]byte(normalizeValue(recordType, value)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (id recordID) String() string {
+	name := normalizeName(id.Name)
+	if name == "" {
+		name = apexName
+	}
+
+	if id.Type == "" || id.ValueHash == "" {
+		return name + "/" + id.Domain
+	}
+	return strings.Join([]string{id.Domain, name, strings.ToUpper(id.Type), id.ValueHash}, "/")
+}
+
+// matches reports whether the record has the name, and the type and value when the id has them
+func (id recordID) matches(record *client.DomainRecord) bool {
+	return sameName(record.Name, id.Name) &&
+		(id.Type == "" || strings.EqualFold(record.Type, id.Type)) &&
+		(id.ValueHash == "" || hashValue(record.Type, record.Value) == id.ValueHash)
 }
 
 func computeFQDN(domain, name string) string {
-	return fmt.Sprintf("%s.%s", strings.ToLower(name), domain)
+	if normalizeName(name) == "" {
+		return domain
+	}
+	return fmt.Sprintf("%s.%s", normalizeName(name), domain)
+}
+
+// apexName is the name of the records of the domain itself, which Freenom names with an empty name
+const apexName = "@"
+
+// normalizeName returns the name of a record as Freenom has it, lowercase and empty for the apex
+func normalizeName(name string) string {
+	if name == apexName {
+		return ""
+	}
+	return strings.ToLower(name)
 }
 
-func getRecordByName(domain, name string, diagnostics *diag.Diagnostics) (record *freenom.DomainRecord, err error) {
+// sameName reports whether the names are the same record name, "@" being the empty name of the apex
+func sameName(a, b string) bool {
+	return normalizeName(a) == normalizeName(b)
+}
 
-	domainInfo, err := freenom.GetDomainInfo(domain)
+// logContext returns ctx with the freenom logging subsystem and the fields of the operation on a domain
+func logContext(ctx context.Context, operation, domain string, fields map[string]interface{}) context.Context {
+	allFields := map[string]interface{}{"operation": operation, "domain": domain}
+	for key, value := range fields {
+		allFields[key] = value
+	}
+	return client.NewLogContext(ctx, allFields)
+}
 
-	if err != nil {
-		diagnostics.AddError(
-			"Error reading domain info: "+domain,
-			err.Error(),
-		)
-		return
+func logDebug(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.SubsystemDebug(ctx, client.LogSubsystem, msg, fields)
+}
+
+// defaultTimeout bounds the operations of a resource without a timeout configured for them
+const defaultTimeout = 5 * time.Minute
+
+// withTimeout returns ctx canceled after the given timeout, or after defaultTimeout when it is not set
+func withTimeout(ctx context.Context, timeout types.String) (context.Context, context.CancelFunc) {
+	d := defaultTimeout
+	if !timeout.Null && !timeout.Unknown {
+		if parsed, err := time.ParseDuration(timeout.Value); err == nil && parsed > 0 {
+			d = parsed
+		}
 	}
+	return context.WithTimeout(ctx, d)
+}
 
-	foundRecord := false
+// addDeferredError adds the error of a data source read while the provider configuration is not known yet
+func addDeferredError(diagnostics *diag.Diagnostics) {
+	diagnostics.AddError(
+		"Provider configuration not known yet",
+		"The freenom provider configuration depends on values known only after apply, so Freenom cannot be read yet. "+
+			"Apply the resources the provider configuration depends on first, for example with -target.",
+	)
+}
 
-	for _, r := range domainInfo.Records {
-		log.Print("[DEBUG] Record: ", r.Name, r.Type, r.Value, r.Priority, r.TTL)
+// clientErrors maps the errors of the Freenom client to their diagnostic,
+// with the attribute causing them when the cause is an input of the configuration
+var clientErrors = []struct {
+	err       error
+	summary   string
+	detail    string
+	attribute string
+}{
+	{
+		err:     client.ErrAuthentication,
+		summary: "Freenom authentication failed",
+		detail:  "Freenom refused the credentials of the provider, the session may have expired and logging in again failed. Check the username and password.",
+	},
+	{
+		err:     client.ErrRateLimited,
+		summary: "Rate limited by Freenom",
+		detail:  "Freenom kept throttling the requests after the retries. Retry later, or lower requests_per_minute in the provider configuration.",
+	},
+	{
+		err:       client.ErrDomainNotFound,
+		summary:   "Domain not found",
+		detail:    "The domain is not registered in the Freenom account of the provider. Check the domain and the account.",
+		attribute: "domain",
+	},
+	{
+		err:       client.ErrRecordNotFound,
+		summary:   "Record not found",
+		detail:    "The domain has no record with this name.",
+		attribute: "name",
+	},
+	{
+		err:       client.ErrDuplicateRecord,
+		summary:   "Record already exists",
+		detail:    "The domain already has a record with the same name, type and value. Import it instead of creating it again.",
+		attribute: "value",
+	},
+	{
+		err:     client.ErrParse,
+		summary: "Unexpected Freenom page",
+		detail:  "A page of Freenom did not have the expected content, likely because Freenom changed it. Please report this issue, attaching a trace written with trace_file.",
+	},
+	{
+		err:     context.DeadlineExceeded,
+		summary: "Freenom timed out",
+		detail:  "Freenom did not complete the operation in time, it can be given more time in the timeouts block of the resource.",
+	},
+}
 
-		if strings.EqualFold(r.Name, name) {
-			foundRecord = true
-			record = r
-			break
+// addClientError adds an error returned by the Freenom client to the diagnostics,
+// summary is used for the errors without a specific diagnostic
+func addClientError(diagnostics *diag.Diagnostics, summary string, err error) {
+	for _, clientError := range clientErrors {
+		if !errors.Is(err, clientError.err) {
+			continue
 		}
+
+		detail := clientError.detail + " Error: " + err.Error()
+
+		if clientError.attribute != "" {
+			diagnostics.AddAttributeError(path.Root(clientError.attribute), clientError.summary, detail)
+		} else {
+			diagnostics.AddError(clientError.summary, detail)
+		}
+		return
 	}
 
-	if !foundRecord {
-		diagnostics.AddError(
-			"Record not found",
-			"Record not found "+computeID(domain, name),
-		)
-		err = fmt.Errorf("record not found")
+	diagnostics.AddError(summary, err.Error())
+}
+
+// getRecordByName returns the only record with the name, and the type and value when they are not empty
+func getRecordByName(ctx context.Context, c FreenomClient, domain, name, recordType, value string, diagnostics *diag.Diagnostics) (record *client.DomainRecord, err error) {
+
+	domainInfo, err := c.GetDomainInfo(ctx, domain)
+
+	if err != nil {
+		addClientError(diagnostics, "Error reading domain info: "+domain, err)
 		return
 	}
+
+	records := matchImportRecords(domainInfo.Records, name, recordType, value)
+
+	logDebug(ctx, "Looked up the record by name", map[string]interface{}{"domain": domain, "name": name, "type": recordType, "found": len(records), "records": len(domainInfo.Records)})
+
+	switch len(records) {
+	case 0:
+		err = fmt.Errorf("%w: %s", client.ErrRecordNotFound, recordID{Domain: domain, Name: name, Type: strings.ToUpper(recordType)})
+		addClientError(diagnostics, "Error reading record", err)
+		return
+	case 1:
+		return records[0], nil
+	}
+
+	candidates := make([]string, 0, len(records))
+	for _, r := range records {
+		candidates = append(candidates, fmt.Sprintf("  %s (%s %s)", computeID(domain, r.Name, r.Type, r.Value), r.Type, r.Value))
+	}
+
+	err = fmt.Errorf("%d records of %s are named %q", len(records), domain, name)
+	diagnostics.AddError(
+		"Ambiguous record",
+		fmt.Sprintf("Several records of %s match the name %q, set the type or the value to pick one of them:\n%s", domain, name, strings.Join(candidates, "\n")),
+	)
 	return
 }
 
-func getAllRecordsByDomainName(domain string, diagnostics *diag.Diagnostics) (records []*freenom.DomainRecord, err error) {
+// findRecord returns the record of the domain identified by id, or an error wrapping client.ErrRecordNotFound
+func findRecord(ctx context.Context, c FreenomClient, id recordID) (*client.DomainRecord, error) {
+	domainInfo, err := c.GetDomainInfo(ctx, id.Domain)
+
+	if err != nil {
+		return nil, err
+	}
 
-	domainInfo, err := freenom.GetDomainInfo(domain)
+	for _, record := range domainInfo.Records {
+		if id.matches(record) {
+			return record, nil
+		}
+	}
+
+	// the value may have been changed outside of terraform, the record is then the only one with its name and type
+	if records := matchImportRecords(domainInfo.Records, id.Name, id.Type, ""); len(records) == 1 {
+		return records[0], nil
+	}
+	return nil, fmt.Errorf("%w: %s", client.ErrRecordNotFound, id)
+}
+
+func getAllRecordsByDomainName(ctx context.Context, c FreenomClient, domain string, diagnostics *diag.Diagnostics) (records []*client.DomainRecord, err error) {
+
+	domainInfo, err := c.GetDomainInfo(ctx, domain)
 
 	if err != nil {
-		diagnostics.AddError(
-			"Error reading domain info: "+domain,
-			err.Error(),
-		)
+		addClientError(diagnostics, "Error reading domain info: "+domain, err)
 		return
 	}
 
-	for _, r := range domainInfo.Records {
-		log.Print("[DEBUG] Record: ", r.Name, r.Type, r.Value, r.Priority, r.TTL)
-		records = append(records, r)
-	}
+	records = append(records, domainInfo.Records...)
+
+	logDebug(ctx, "Read the records of the domain", map[string]interface{}{"domain": domain, "records": len(records)})
 	return
 }
 
-func getAllRecordsByDomainNameAndValue(domain string, value string, diagnostics *diag.Diagnostics) (records []*freenom.DomainRecord, err error) {
+func getAllRecordsByDomainNameAndValue(ctx context.Context, c FreenomClient, domain string, value string, diagnostics *diag.Diagnostics) (records []*client.DomainRecord, err error) {
 
-	domainInfo, err := freenom.GetDomainInfo(domain)
+	domainInfo, err := c.GetDomainInfo(ctx, domain)
 
 	if err != nil {
-		diagnostics.AddError(
-			"Error reading domain info: "+domain,
-			err.Error(),
-		)
+		addClientError(diagnostics, "Error reading domain info: "+domain, err)
 		return
 	}
 
 	for _, r := range domainInfo.Records {
-		log.Print("[DEBUG] Record: ", r.Name, r.Type, r.Value, r.Priority, r.TTL)
-		if r.Value == value {
+		if sameValue(r.Type, r.Value, value) {
 			records = append(records, r)
 		}
 	}
@@ -0,0 +1,109 @@
+package freenom
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-frenom/freenom/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAddClientError(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		summary   string
+		attribute string
+	}{
+		"authentication":   {err: client.ErrAuthentication, summary: "Freenom authentication failed"},
+		"rate limited":     {err: client.ErrRateLimited, summary: "Rate limited by Freenom"},
+		"domain not found": {err: client.ErrDomainNotFound, summary: "Domain not found", attribute: "domain"},
+		"record not found": {err: client.ErrRecordNotFound, summary: "Record not found", attribute: "name"},
+		"duplicate record": {err: client.ErrDuplicateRecord, summary: "Record already exists", attribute: "value"},
+		"parse":            {err: client.ErrParse, summary: "Unexpected Freenom page"},
+		"timeout":          {err: context.DeadlineExceeded, summary: "Freenom timed out"},
+		"other":            {err: fmt.Errorf("connection refused"), summary: "Error creating record"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addClientError(&diags, "Error creating record", fmt.Errorf("adding records to example.tk: %w", test.err))
+
+			if len(diags) != 1 || diags[0].Summary() != test.summary {
+				t.Fatalf("expected a %q diagnostic, got %v", test.summary, diags)
+			}
+
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+
+			if test.attribute == "" {
+				if ok {
+					t.Errorf("expected no attribute, got %s", withPath.Path())
+				}
+				return
+			}
+
+			if !ok || !withPath.Path().Equal(path.Root(test.attribute)) {
+				t.Errorf("expected the diagnostic to point to %s, got %v", test.attribute, diags[0])
+			}
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		expected recordID
+		err      bool
+	}{
+		"full": {
+			id:       "example.tk/www/A/0123456789abcdef",
+			expected: recordID{Domain: "example.tk", Name: "www", Type: "A", ValueHash: "0123456789abcdef"},
+		},
+		"legacy": {
+			id:       "www/example.tk",
+			expected: recordID{Domain: "example.tk", Name: "www"},
+		},
+		"invalid": {
+			id:  "www",
+			err: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, err := parseID(test.id)
+
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", test.id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if id != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, id)
+			}
+			if id.String() != test.id {
+				t.Errorf("expected %q, got %q", test.id, id.String())
+			}
+		})
+	}
+}
+
+func TestComputeFQDN(t *testing.T) {
+	tests := map[string]string{
+		"WWW": "www.example.tk",
+		"@":   "example.tk",
+		"":    "example.tk",
+	}
+
+	for name, expected := range tests {
+		if fqdn := computeFQDN("example.tk", name); fqdn != expected {
+			t.Errorf("expected %q for %q, got %q", expected, name, fqdn)
+		}
+	}
+}
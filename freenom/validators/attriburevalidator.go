@@ -1,10 +1,14 @@
 package validators
 
 import (
+	"context"
+	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func IsDomain() tfsdk.AttributeValidator {
@@ -14,11 +18,10 @@ func IsDomain() tfsdk.AttributeValidator {
 	)
 }
 
+var reIpv4 = regexp.MustCompile(`^(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)(\.(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)){3}$`)
+
 func IsIpv4() tfsdk.AttributeValidator {
-	return stringvalidator.RegexMatches(
-		regexp.MustCompile(`(\b25[0-5]|\b2[0-4][0-9]|\b[01]?[0-9][0-9]?)(\.(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)){3}`),
-		"Invalid ipv4",
-	)
+	return stringvalidator.RegexMatches(reIpv4, "Invalid ipv4")
 }
 
 func IsMacAddress() tfsdk.AttributeValidator {
@@ -27,3 +30,34 @@ func IsMacAddress() tfsdk.AttributeValidator {
 		"Invalid mac address",
 	)
 }
+
+func IsDuration() tfsdk.AttributeValidator {
+	return durationValidator{}
+}
+
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration (Ex. 30s, 5m)"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var value types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &value)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || value.Null || value.Unknown {
+		return
+	}
+
+	if d, err := time.ParseDuration(value.Value); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.AttributePath,
+			"Invalid duration",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.AttributePath, v.Description(ctx), value.Value),
+		)
+	}
+}
@@ -0,0 +1,144 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// MaxTXTLength is the longest DNS character string, a TXT value longer than it is split in quoted strings
+const MaxTXTLength = 255
+
+// reTXTStrings matches a TXT value of quoted character strings separated by spaces (Ex. "v=DKIM1; p=..." "...")
+var reTXTStrings = regexp.MustCompile(`^"(?:[^"\\]|\\.)*"(?:\s+"(?:[^"\\]|\\.)*")*$`)
+var reTXTString = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+var reHostname = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_\-]{0,61}[A-Za-z0-9_])?(\.[A-Za-z0-9_]([A-Za-z0-9_\-]{0,61}[A-Za-z0-9_])?)*\.?$`)
+
+// reLOC matches the LOC values of RFC 1876: latitude, longitude, altitude and optionally size and precisions
+var reLOC = regexp.MustCompile(`^\d{1,2}(\s+\d{1,2}(\s+\d{1,2}(\.\d{1,3})?)?)?\s+[NS]\s+\d{1,3}(\s+\d{1,2}(\s+\d{1,2}(\.\d{1,3})?)?)?\s+[EW]\s+-?\d+(\.\d{1,2})?m?(\s+\d+(\.\d{1,2})?m?){0,3}$`)
+
+// reNAPTR matches the NAPTR values of RFC 3403: order, preference, flags, service, regexp and replacement
+var reNAPTR = regexp.MustCompile(`^(\d{1,5})\s+(\d{1,5})\s+"[A-Za-z0-9]*"\s+"[^"]*"\s+"[^"]*"\s+(\S+)$`)
+
+// recordValueChecks validate the value of the records of every type, they return what a valid value is
+var recordValueChecks = map[string]func(value string) (bool, string){
+	"A": func(value string) (bool, string) {
+		return reIpv4.MatchString(value), "must be an IPv4 address (Ex. 10.10.10.10)"
+	},
+	"AAAA": func(value string) (bool, string) {
+		return strings.Contains(value, ":") && net.ParseIP(value) != nil, "must be an IPv6 address (Ex. 2001:db8::1)"
+	},
+	"CNAME": func(value string) (bool, string) {
+		return isHostname(value), "must be a hostname (Ex. www.example.com)"
+	},
+	"MX": func(value string) (bool, string) {
+		return isHostname(value), "must be the hostname of the mail server (Ex. mail.example.com)"
+	},
+	"TXT": func(value string) (bool, string) {
+		return isTXT(value), fmt.Sprintf("must be between 1 and %d characters long, or quoted strings of at most %d characters each", MaxTXTLength, MaxTXTLength)
+	},
+	"LOC": func(value string) (bool, string) {
+		return reLOC.MatchString(value), "must be a location as in RFC 1876 (Ex. 52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m)"
+	},
+	"NAPTR": func(value string) (bool, string) {
+		return isNAPTR(value), `must be order, preference, "flags", "service", "regexp" and replacement (Ex. 100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .)`
+	},
+	"RP": func(value string) (bool, string) {
+		fields := strings.Fields(value)
+		return len(fields) == 2 && isDomainName(fields[0]) && isDomainName(fields[1]),
+			"must be the mailbox and the TXT record of the responsible person (Ex. admin.example.com. info.example.com.)"
+	},
+}
+
+// CheckRecordValue returns an error when the value is not valid for the type of record,
+// the values of unknown types are not checked
+func CheckRecordValue(recordType, value string) error {
+	check, ok := recordValueChecks[recordType]
+	if !ok {
+		return nil
+	}
+
+	if valid, description := check(value); !valid {
+		return fmt.Errorf("the value of a %s record %s, got: %q", recordType, description, value)
+	}
+	return nil
+}
+
+func isHostname(value string) bool {
+	return len(value) <= 254 && reHostname.MatchString(value)
+}
+
+// isDomainName reports whether value is a hostname or the root "."
+func isDomainName(value string) bool {
+	return value == "." || isHostname(value)
+}
+
+// isTXT reports whether every character string of the value fits in a DNS character string,
+// an unquoted value is a single string
+func isTXT(value string) bool {
+	if !reTXTStrings.MatchString(value) {
+		return value != "" && len(value) <= MaxTXTLength
+	}
+
+	for _, match := range reTXTString.FindAllStringSubmatch(value, -1) {
+		if len(match[1]) > MaxTXTLength {
+			return false
+		}
+	}
+	return true
+}
+
+func isNAPTR(value string) bool {
+	matches := reNAPTR.FindStringSubmatch(value)
+	if matches == nil {
+		return false
+	}
+
+	order, _ := strconv.Atoi(matches[1])
+	preference, _ := strconv.Atoi(matches[2])
+	return order <= 65535 && preference <= 65535 && isDomainName(matches[3])
+}
+
+// RecordValue validates the value attribute of a record by the type of the record
+func RecordValue(typePath, valuePath path.Path) resource.ConfigValidator {
+	return recordValueValidator{typePath: typePath, valuePath: valuePath}
+}
+
+type recordValueValidator struct {
+	typePath  path.Path
+	valuePath path.Path
+}
+
+func (v recordValueValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("%s must be valid for the record type of %s", v.valuePath, v.typePath)
+}
+
+func (v recordValueValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v recordValueValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var recordType, value types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, v.typePath, &recordType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, v.valuePath, &value)...)
+	if resp.Diagnostics.HasError() || recordType.Null || recordType.Unknown || value.Null || value.Unknown {
+		return
+	}
+
+	if err := CheckRecordValue(recordType.Value, value.Value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			v.valuePath,
+			"Invalid record value",
+			fmt.Sprintf("Attribute %s is invalid, %s", v.valuePath, err),
+		)
+	}
+}
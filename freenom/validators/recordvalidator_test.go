@@ -0,0 +1,56 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRecordValue(t *testing.T) {
+	tests := []struct {
+		recordType string
+		value      string
+		valid      bool
+	}{
+		{"A", "10.10.10.10", true},
+		{"A", "10.10.10.256", false},
+		{"A", "10.10.10.10.10", false},
+		{"A", "2001:db8::1", false},
+		{"AAAA", "2001:db8::1", true},
+		{"AAAA", "10.10.10.10", false},
+		{"AAAA", "2001:db8:::1", false},
+		{"CNAME", "www.example.tk.", true},
+		{"CNAME", "selector._domainkey.example.tk", true},
+		{"CNAME", "-www.example.tk", false},
+		{"CNAME", "http://example.tk", false},
+		{"MX", "mail.example.tk", true},
+		{"MX", "mail example.tk", false},
+		{"TXT", "v=spf1 include:_spf.example.tk ~all", true},
+		{"TXT", "", false},
+		{"TXT", strings.Repeat("a", MaxTXTLength+1), false},
+		{"TXT", `"v=DKIM1; k=rsa; p=` + strings.Repeat("A", 230) + `" "` + strings.Repeat("B", 150) + `"`, true},
+		{"TXT", `"v=DKIM1; k=rsa; p=` + strings.Repeat("A", 240) + `" "` + strings.Repeat("B", 150) + `"`, false},
+		{"TXT", `"` + strings.Repeat("a", MaxTXTLength) + `"`, true},
+		{"LOC", "52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m", true},
+		{"LOC", "52 N 4 E 10m", true},
+		{"LOC", "52 22 23 4 53 32 -2m", false},
+		{"NAPTR", `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.tk!" .`, true},
+		{"NAPTR", `100 10 "s" "SIP+D2U" "" _sip._udp.example.tk.`, true},
+		{"NAPTR", `100 70000 "u" "E2U+sip" "" .`, false},
+		{"NAPTR", `100 10 u E2U+sip "" .`, false},
+		{"RP", "admin.example.tk. info.example.tk.", true},
+		{"RP", "admin.example.tk. .", true},
+		{"RP", "admin.example.tk.", false},
+		{"SRV", "anything", true},
+	}
+
+	for _, test := range tests {
+		err := CheckRecordValue(test.recordType, test.value)
+
+		if test.valid && err != nil {
+			t.Errorf("expected %s %q to be valid, got %s", test.recordType, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected %s %q to be invalid", test.recordType, test.value)
+		}
+	}
+}